@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 
@@ -21,6 +22,12 @@ func run() int {
 	if err := cmd.Execute(); err != nil {
 		_, _ = color.New(color.FgHiRed, color.Bold).Fprintln(os.Stderr, err.Error())
 
+		// the commands might describe the outcome with the exit code, e.g. `rr stop`
+		var ec interface{ ExitCode() int }
+		if errors.As(err, &ec) {
+			return ec.ExitCode()
+		}
+
 		return 1
 	}
 
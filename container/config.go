@@ -5,49 +5,94 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/roadrunner-server/roadrunner/v2025/internal/debug"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/loader"
 	"github.com/spf13/viper"
 )
 
 // Config defines endure container configuration.
 type Config struct {
-	GracePeriod time.Duration `mapstructure:"grace_period"`
-	LogLevel    string        `mapstructure:"log_level"`
-	WatchdogSec int           `mapstructure:"watchdog_sec"`
-	PrintGraph  bool          `mapstructure:"print_graph"`
+	GracePeriod      time.Duration `mapstructure:"grace_period"`
+	LogLevel         string        `mapstructure:"log_level"`
+	WatchdogSec      int           `mapstructure:"watchdog_sec"`
+	WatchdogCritical []string      `mapstructure:"watchdog_critical_plugins"`
+	PrintGraph       bool          `mapstructure:"print_graph"`
+	PidFile          string        `mapstructure:"pid_file"`
+	RestartMode      string        `mapstructure:"restart_mode"`
+	RestartTimeout   time.Duration `mapstructure:"restart_timeout"`
+	UpgradeBinary    string        `mapstructure:"upgrade_binary"`
+	Debug            debug.Config  `mapstructure:"debug"`
 }
 
+const (
+	// RestartExec stops the container and re-executes the binary on SIGUSR2.
+	RestartExec string = "exec"
+	// RestartGraceful starts the new process and stops the current one once the new process is ready. Only the
+	// listeners created via handoff.Listen are inherited, the plugins bind their sockets again.
+	RestartGraceful string = "graceful"
+	// RestartUpgrade is the graceful restart with the new binary, which is checked before it's started.
+	RestartUpgrade string = "upgrade"
+)
+
 const (
 	// endure config key
 	endureKey = "endure"
 	// overall grace period, after which container will be stopped forcefully
 	defaultGracePeriod = time.Second * 30
+	// how long to wait for the new process to become ready
+	defaultRestartTimeout = time.Minute
 )
 
-// NewConfig creates endure container configuration.
-func NewConfig(cfgFile string) (*Config, error) {
-	v := viper.New()
-	v.SetConfigFile(cfgFile)
-
-	err := v.ReadInConfig()
+// NewConfig creates endure container configuration. The configuration file is resolved the same way as for the
+// plugins: includes, ${ENV} references and -o overrides are taken into account. Unlike the plugins configuration, the
+// version is not required.
+func NewConfig(cfgFile string, overrides ...string) (*Config, error) {
+	resolved, err := loader.LoadLenient(cfgFile, overrides)
 	if err != nil {
 		return nil, err
 	}
 
+	return ParseConfig(resolved.Viper())
+}
+
+// ParseConfig creates endure container configuration from the already resolved configuration.
+func ParseConfig(v *viper.Viper) (*Config, error) {
 	cfg := &Config{
-		GracePeriod: defaultGracePeriod,
-		LogLevel:    "error",
-		PrintGraph:  false,
+		GracePeriod:    defaultGracePeriod,
+		LogLevel:       "error",
+		PrintGraph:     false,
+		RestartMode:    RestartExec,
+		RestartTimeout: defaultRestartTimeout,
 	}
 
 	if !v.IsSet(endureKey) {
+		cfg.Debug.InitDefaults()
 		return cfg, nil
 	}
 
-	err = v.UnmarshalKey(endureKey, cfg)
+	err := v.UnmarshalKey(endureKey, cfg)
 	if err != nil {
 		return nil, err
 	}
 
+	switch cfg.RestartMode {
+	case RestartExec, RestartGraceful, RestartUpgrade:
+	case "":
+		cfg.RestartMode = RestartExec
+	default:
+		return nil, fmt.Errorf(`unknown restart mode "%s" (allowed: %s, %s, %s)`, cfg.RestartMode, RestartExec, RestartGraceful, RestartUpgrade)
+	}
+
+	if cfg.RestartTimeout <= 0 {
+		cfg.RestartTimeout = defaultRestartTimeout
+	}
+
+	cfg.Debug.InitDefaults()
+
+	if err = cfg.Debug.Validate(); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
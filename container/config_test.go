@@ -72,3 +72,63 @@ func TestNewConfig_LoggingLevels(t *testing.T) {
 		})
 	}
 }
+
+func TestNewConfig_RestartMode(t *testing.T) {
+	c, err := container.NewConfig("test/endure_ok.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, container.RestartExec, c.RestartMode)
+
+	assert.Equal(t, time.Minute, c.RestartTimeout)
+
+	c, err = container.NewConfig("test/endure_restart_graceful.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, container.RestartGraceful, c.RestartMode)
+
+	c, err = container.NewConfig("test/endure_restart_upgrade.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, container.RestartUpgrade, c.RestartMode)
+	assert.Equal(t, time.Second*20, c.RestartTimeout)
+	assert.Equal(t, "/usr/local/bin/rr", c.UpgradeBinary)
+
+	_, err = container.NewConfig("test/endure_restart_unknown.yaml")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown restart mode "fork"`)
+}
+
+func TestNewConfig_WatchdogCritical(t *testing.T) {
+	c, err := container.NewConfig("test/endure_ok.yaml")
+	assert.NoError(t, err)
+	assert.Empty(t, c.WatchdogCritical)
+
+	c, err = container.NewConfig("test/endure_watchdog.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, 10, c.WatchdogSec)
+	assert.Equal(t, []string{"http", "jobs"}, c.WatchdogCritical)
+}
+
+func TestNewConfig_Debug(t *testing.T) {
+	c, err := container.NewConfig("test/endure_ok.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:6061", c.Debug.Address)
+	assert.False(t, c.Debug.Profiler.Enabled)
+	assert.Equal(t, "rr-profiles", c.Debug.Profiler.Dir)
+
+	c, err = container.NewConfig("test/endure_debug.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "unix:///run/rr-debug.sock", c.Debug.Address)
+	assert.Equal(t, "token", c.Debug.BearerToken)
+	assert.Equal(t, []string{"10.0.0.0/8"}, c.Debug.AllowedIPs)
+	assert.Equal(t, 1000, c.Debug.BlockProfileRate)
+	assert.Equal(t, 10, c.Debug.MutexProfileFraction)
+
+	assert.True(t, c.Debug.Profiler.Enabled)
+	assert.Equal(t, "/var/lib/rr/profiles", c.Debug.Profiler.Dir)
+	assert.Equal(t, int64(1048576), c.Debug.Profiler.MaxSize)
+	assert.Equal(t, uint64(536870912), c.Debug.Profiler.HeapThreshold)
+	assert.Equal(t, time.Minute*10, c.Debug.Profiler.Interval)
+	assert.Equal(t, time.Second*10, c.Debug.Profiler.CPUDuration)
+
+	_, err = container.NewConfig("test/endure_debug_invalid.yaml")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "both username and password")
+}
@@ -0,0 +1,248 @@
+package activation
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/roadrunner-server/errors"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/handoff"
+)
+
+const (
+	// Prefix of the address referencing the socket passed by systemd, e.g. systemd:http.
+	Prefix string = "systemd:"
+
+	envPid   string = "LISTEN_PID"
+	envFDs   string = "LISTEN_FDS"
+	envNames string = "LISTEN_FDNAMES"
+
+	// SD_LISTEN_FDS_START
+	firstFD = 3
+	// the name of the socket without FileDescriptorName=
+	unknownName = "unknown"
+)
+
+// Listeners lists the configuration options listened via Listen. The plugins bind their sockets on their own, so
+// systemd:NAME is rejected in their sections.
+var Listeners = []string{"endure.debug.address"} //nolint:gochecknoglobals
+
+// the sockets passed by systemd or by the previous process on the graceful restart.
+var (
+	mu       sync.Mutex                  //nolint:gochecknoglobals
+	once     sync.Once                   //nolint:gochecknoglobals
+	sockets  map[string]*os.File         //nolint:gochecknoglobals
+	setupErr error                       //nolint:gochecknoglobals
+	created  = map[string]net.Listener{} //nolint:gochecknoglobals
+)
+
+// Setup takes over the sockets passed by systemd. It should be called before any worker is started, so the
+// descriptors don't leak into the workers, the other functions call it implicitly. The sockets are passed to the new
+// process on the graceful restart.
+func Setup() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	once.Do(parse)
+
+	return setupErr
+}
+
+// Names returns the sorted names of the passed sockets.
+func Names() []string {
+	mu.Lock()
+	defer mu.Unlock()
+
+	once.Do(parse)
+
+	names := make([]string, 0, len(sockets))
+	for name := range sockets {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// Listen returns the listener of the socket passed by systemd for the systemd:NAME address, the other addresses are
+// passed to handoff.Listen.
+func Listen(address string) (net.Listener, error) {
+	name, ok := strings.CutPrefix(address, Prefix)
+	if !ok {
+		return handoff.Listen(address)
+	}
+
+	return Listener(name)
+}
+
+// Listener returns the listener of the socket passed by systemd with the name (FileDescriptorName=).
+func Listener(name string) (net.Listener, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	once.Do(parse)
+
+	if setupErr != nil {
+		return nil, setupErr
+	}
+
+	if l, ok := created[name]; ok {
+		return l, nil
+	}
+
+	f, ok := sockets[name]
+	if !ok {
+		return nil, missing(name)
+	}
+
+	// the file is kept: it's passed to the new process on restart
+	l, err := net.FileListener(f)
+	if err != nil {
+		return nil, errors.Errorf("socket %s passed by systemd: %v", name, err)
+	}
+
+	created[name] = l
+
+	return l, nil
+}
+
+// Check returns an error when the configuration references a socket which is not passed by systemd or references it
+// in the option which is not listened via Listen (see Listeners).
+func Check(settings map[string]any) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	once.Do(parse)
+
+	if setupErr != nil {
+		return setupErr
+	}
+
+	var problems []string
+	walk("", settings, func(path, name string) {
+		if !slices.Contains(Listeners, path) {
+			problems = append(problems, fmt.Sprintf("%s: %s%s is not supported, the sockets passed by systemd are used only by %s", path, Prefix, name, strings.Join(Listeners, ", ")))
+			return
+		}
+
+		if _, ok := sockets[name]; !ok {
+			problems = append(problems, fmt.Sprintf("%s: %v", path, missing(name)))
+		}
+	})
+
+	if len(problems) > 0 {
+		slices.Sort(problems)
+		return errors.Errorf("socket activation:\n%s", strings.Join(problems, "\n"))
+	}
+
+	return nil
+}
+
+// walk calls fn for every systemd:NAME value of the configuration.
+func walk(path string, val any, fn func(path, name string)) {
+	switch v := val.(type) {
+	case map[string]any:
+		for key, item := range v {
+			walk(join(path, key), item, fn)
+		}
+	case []any:
+		for i, item := range v {
+			walk(join(path, strconv.Itoa(i)), item, fn)
+		}
+	case string:
+		if name, ok := strings.CutPrefix(v, Prefix); ok {
+			fn(path, name)
+		}
+	}
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+func missing(name string) error {
+	if len(sockets) == 0 {
+		return errors.Errorf("socket %q is not passed by systemd: no sockets are passed (LISTEN_FDS is not set), start RoadRunner via the socket unit", name)
+	}
+
+	names := make([]string, 0, len(sockets))
+	for n := range sockets {
+		names = append(names, n)
+	}
+
+	slices.Sort(names)
+
+	return errors.Errorf("socket %q is not passed by systemd (passed: %s), set FileDescriptorName=%s in the socket unit", name, strings.Join(names, ", "), name)
+}
+
+// parse reads the sockets passed by systemd, the variables are unset so they aren't leaked into the workers. On the
+// graceful restart, the sockets are inherited from the previous process instead.
+func parse() {
+	sockets = make(map[string]*os.File)
+
+	pid, fds, names := os.Getenv(envPid), os.Getenv(envFDs), os.Getenv(envNames)
+	_ = os.Unsetenv(envPid)
+	_ = os.Unsetenv(envFDs)
+	_ = os.Unsetenv(envNames)
+
+	if fds == "" {
+		for _, name := range handoff.InheritedNames() {
+			if n, ok := strings.CutPrefix(name, Prefix); ok {
+				adopt(n, handoff.Inherited(name))
+			}
+		}
+
+		return
+	}
+
+	// the sockets are passed to another process, e.g. the parent didn't unset the variables
+	if p, err := strconv.Atoi(pid); err != nil || p != os.Getpid() {
+		return
+	}
+
+	n, err := strconv.Atoi(fds)
+	if err != nil || n < 0 {
+		setupErr = errors.Errorf("invalid %s: %q", envFDs, fds)
+		return
+	}
+
+	var list []string
+	if names != "" {
+		list = strings.Split(names, ":")
+	}
+
+	if len(list) != n {
+		// the names are optional, e.g. the older systemd
+		list = slices.Repeat([]string{unknownName}, n)
+	}
+
+	for i, name := range list {
+		if _, ok := sockets[name]; ok {
+			setupErr = errors.Errorf("multiple sockets named %q are passed by systemd, set the unique FileDescriptorName= for every socket", name)
+			return
+		}
+
+		fd := firstFD + i
+		closeOnExec(fd)
+		adopt(name, os.NewFile(uintptr(fd), Prefix+name)) //nolint:gosec
+	}
+}
+
+// adopt registers the socket, so it's passed to the new process on the graceful restart.
+func adopt(name string, f *os.File) {
+	if f == nil {
+		return
+	}
+
+	sockets[name] = f
+	handoff.Register(Prefix+name, f)
+}
@@ -0,0 +1,112 @@
+//go:build !windows
+
+package activation_test
+
+import (
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+
+	"github.com/roadrunner-server/roadrunner/v2025/internal/activation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// the test binary plays the activated server, the sockets are parsed once per process
+const childEnv = "RR_ACTIVATION_TEST_CHILD"
+
+func TestNotActivated(t *testing.T) {
+	if os.Getenv(childEnv) != "" {
+		t.Skip("the parent test")
+	}
+
+	require.NoError(t, activation.Setup())
+	assert.Empty(t, activation.Names())
+
+	err := activation.Check(map[string]any{"endure": map[string]any{"debug": map[string]any{"address": "systemd:debug"}}, "rpc": map[string]any{"listen": "tcp://127.0.0.1:6001"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `endure.debug.address: socket "debug" is not passed by systemd: no sockets are passed`)
+
+	_, err = activation.Listen("systemd:http")
+	require.Error(t, err)
+}
+
+func TestActivated(t *testing.T) {
+	if os.Getenv(childEnv) != "" {
+		t.Skip("the parent test")
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	f, err := ln.(*net.TCPListener).File()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+
+	// systemd passes the sockets starting from the descriptor 3
+	cmd := exec.Command(os.Args[0], "-test.run=^TestActivatedChild$", "-test.v")
+	cmd.Env = append(os.Environ(), childEnv+"=1", "LISTEN_FDS=1", "LISTEN_FDNAMES=http")
+	cmd.ExtraFiles = []*os.File{f}
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	assert.Contains(t, string(out), "--- PASS: TestActivatedChild")
+}
+
+func TestActivatedChild(t *testing.T) {
+	if os.Getenv(childEnv) == "" {
+		t.Skip("started by TestActivated")
+	}
+
+	// set by systemd after the fork
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+
+	require.NoError(t, activation.Setup())
+	assert.Equal(t, []string{"http"}, activation.Names())
+
+	// the variables are not leaked into the workers
+	_, ok := os.LookupEnv("LISTEN_FDS")
+	assert.False(t, ok)
+
+	require.NoError(t, activation.Check(map[string]any{"endure": map[string]any{"debug": map[string]any{"address": "systemd:http"}}}))
+
+	err := activation.Check(map[string]any{"endure": map[string]any{"debug": map[string]any{"address": "systemd:grpc"}}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `endure.debug.address: socket "grpc" is not passed by systemd (passed: http), set FileDescriptorName=grpc in the socket unit`)
+
+	// the plugins bind their sockets on their own, even the passed socket is rejected
+	err = activation.Check(map[string]any{"http": map[string]any{"address": "systemd:http"}, "grpc": map[string]any{"listen": []any{"systemd:grpc"}}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "grpc.listen.0: systemd:grpc is not supported, the sockets passed by systemd are used only by endure.debug.address")
+	assert.Contains(t, err.Error(), "http.address: systemd:http is not supported")
+
+	l, err := activation.Listen("systemd:http")
+	require.NoError(t, err)
+
+	// the same listener is returned for every reference
+	l2, err := activation.Listener("http")
+	require.NoError(t, err)
+	assert.Same(t, l, l2)
+
+	go func() {
+		conn, errA := l.Accept()
+		if errA != nil {
+			return
+		}
+
+		_, _ = conn.Write([]byte("hello"))
+		_ = conn.Close()
+	}()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	_ = conn.Close()
+}
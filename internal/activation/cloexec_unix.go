@@ -0,0 +1,11 @@
+//go:build !windows
+
+package activation
+
+import (
+	"syscall"
+)
+
+func closeOnExec(fd int) {
+	syscall.CloseOnExec(fd)
+}
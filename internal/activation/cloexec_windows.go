@@ -0,0 +1,4 @@
+package activation
+
+// no socket activation on Windows
+func closeOnExec(int) {}
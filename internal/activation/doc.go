@@ -0,0 +1,9 @@
+// Package activation implements the systemd socket activation: the sockets
+// passed by systemd (LISTEN_FDS, LISTEN_FDNAMES) are referenced in the
+// configuration by the name, e.g. `address: systemd:debug`, so systemd owns
+// the ports. Only the listeners created by RoadRunner itself (the debug
+// server) support such addresses, the plugins bind their sockets on their own
+// and the references in their sections are rejected.
+//
+// https://www.freedesktop.org/software/systemd/man/sd_listen_fds.html
+package activation
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates `config` command.
+func NewCommand(cfgFile *string, override *[]string, silent *bool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Configuration file tools",
+	}
+
+	cmd.AddCommand(
+		newValidateCommand(cfgFile, override, silent),
+		newDumpCommand(cfgFile, override),
+		newMigrateCommand(cfgFile, silent),
+	)
+
+	return cmd
+}
@@ -0,0 +1,5 @@
+// Package config implements the "config" command with tools for working with
+// the RoadRunner configuration file, such as validating it against the
+// bundled JSON schema and printing the effective configuration with the
+// origin of every value.
+package config
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/roadrunner-server/errors"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/loader"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	formatYAML string = "yaml"
+	formatJSON string = "json"
+)
+
+func newDumpCommand(cfgFile *string, override *[]string) *cobra.Command {
+	var (
+		format      string
+		explain     bool
+		showSecrets bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "dump",
+		Short: "Print the effective configuration (includes, env variables and overrides applied)",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			const op = errors.Op("config_dump")
+
+			if cfgFile == nil {
+				return errors.E(op, errors.Str("no configuration file provided"))
+			}
+
+			if format != formatYAML && format != formatJSON {
+				return errors.E(op, errors.Errorf("unknown format %q (allowed: yaml, json)", format))
+			}
+
+			var flags []string
+			if override != nil {
+				flags = *override
+			}
+
+			c, err := loader.Load(*cfgFile, flags)
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			settings := c.Settings()
+
+			var doc any = settings
+			if !showSecrets {
+				doc = redact(settings, "", "", c)
+			}
+
+			switch format {
+			case formatJSON:
+				err = dumpJSON(cmd.OutOrStdout(), doc, c, explain)
+			default:
+				err = dumpYAML(cmd.OutOrStdout(), doc, c, explain)
+			}
+
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", formatYAML, "output format: yaml or json")
+	cmd.Flags().BoolVar(&explain, "explain", false, "annotate every value with its origin")
+	cmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "do not redact passwords, tokens and DSNs")
+
+	return cmd
+}
+
+func dumpYAML(w io.Writer, doc any, c *loader.Config, explain bool) error {
+	node := toNode(doc, "", c, explain)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(node); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+// toNode converts the configuration into the YAML tree, with the origins as line comments when explain is set.
+func toNode(doc any, key string, c *loader.Config, explain bool) *yaml.Node {
+	switch t := doc.(type) {
+	case map[string]any:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		for _, k := range sortedKeys(t) {
+			full := joinKey(key, k)
+
+			keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: k}
+			valNode := toNode(t[k], full, c, explain)
+
+			if explain {
+				if _, isMap := t[k].(map[string]any); !isMap {
+					if o, ok := c.Origin(full); ok {
+						keyNode.LineComment = o.String()
+					}
+				}
+			}
+
+			node.Content = append(node.Content, keyNode, valNode)
+		}
+
+		return node
+	default:
+		node := &yaml.Node{}
+		if err := node.Encode(t); err != nil {
+			return &yaml.Node{Kind: yaml.ScalarNode, Value: fmt.Sprint(t)}
+		}
+
+		return node
+	}
+}
+
+func dumpJSON(w io.Writer, doc any, c *loader.Config, explain bool) error {
+	if explain {
+		doc = explainJSON(doc, "", c)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(doc)
+}
+
+// explainJSON replaces every leaf value with the {"value": ..., "origin": ...} object.
+func explainJSON(doc any, key string, c *loader.Config) any {
+	if m, ok := doc.(map[string]any); ok {
+		res := make(map[string]any, len(m))
+		for k, v := range m {
+			res[k] = explainJSON(v, joinKey(key, k), c)
+		}
+
+		return res
+	}
+
+	origin := ""
+	if o, ok := c.Origin(key); ok {
+		origin = o.String()
+	}
+
+	return map[string]any{
+		"value":  doc,
+		"origin": origin,
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return strings.Join([]string{prefix, key}, ".")
+}
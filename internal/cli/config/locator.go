@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// locator finds the file and the line where a configuration value was defined.
+type locator struct {
+	// files in the order of precedence: the last one wins
+	files []string
+	nodes []*yaml.Node
+	// override keys passed via -o
+	overrides []string
+}
+
+func newLocator(files []string, overrides []string) *locator {
+	l := &locator{}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		node := &yaml.Node{}
+		if err = yaml.Unmarshal(data, node); err != nil {
+			continue
+		}
+
+		l.files = append(l.files, file)
+		l.nodes = append(l.nodes, node)
+	}
+
+	for _, o := range overrides {
+		key, _, _ := strings.Cut(o, "=")
+		l.overrides = append(l.overrides, strings.ToLower(strings.Trim(key, " \"'`\n\t")))
+	}
+
+	return l
+}
+
+// locate returns the position of the value in the form of file:line.
+func (l *locator) locate(path []string) string {
+	dotted := strings.Join(path, ".")
+	for i := len(l.overrides) - 1; i >= 0; i-- {
+		if dotted == l.overrides[i] || strings.HasPrefix(dotted, l.overrides[i]+".") {
+			return fmt.Sprintf("-o %s", l.overrides[i])
+		}
+	}
+
+	// the deepest match wins, on equal depth the later file wins
+	bestFile, bestLine, bestDepth := "", 0, -1
+	for i := len(l.nodes) - 1; i >= 0; i-- {
+		line, depth := find(l.nodes[i], path)
+		if depth > bestDepth {
+			bestFile, bestLine, bestDepth = l.files[i], line, depth
+		}
+	}
+
+	if bestFile == "" {
+		return "<unknown>"
+	}
+
+	return fmt.Sprintf("%s:%d", bestFile, bestLine)
+}
+
+// find walks the YAML document and returns the line of the deepest node matching the path and the matched depth.
+func find(node *yaml.Node, path []string) (int, int) {
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return node.Line, 0
+		}
+		node = node.Content[0]
+	}
+
+	line := node.Line
+	for depth, p := range path {
+		for node.Kind == yaml.AliasNode && node.Alias != nil {
+			node = node.Alias
+		}
+
+		var next *yaml.Node
+
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if strings.EqualFold(node.Content[i].Value, p) {
+					line = node.Content[i].Line
+					next = node.Content[i+1]
+
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if idx, err := strconv.Atoi(p); err == nil && idx >= 0 && idx < len(node.Content) {
+				next = node.Content[idx]
+				line = next.Line
+			}
+		default:
+		}
+
+		if next == nil {
+			return line, depth
+		}
+
+		node = next
+	}
+
+	return line, len(path)
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/roadrunner-server/errors"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/migrate"
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCommand(cfgFile *string, silent *bool) *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade the configuration file written for an older version (1.0, 2.x) to version " + migrate.CurrentVersion,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			const op = errors.Op("config_migrate")
+
+			if cfgFile == nil {
+				return errors.E(op, errors.Str("no configuration file provided"))
+			}
+
+			fi, err := os.Stat(*cfgFile)
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			data, err := os.ReadFile(*cfgFile)
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			out, warnings, err := migrate.Migrate(data)
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			for _, w := range warnings {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s\n", w)
+			}
+
+			if bytes.Equal(data, out) {
+				if silent == nil || !*silent {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s: already at version %s\n", *cfgFile, migrate.CurrentVersion)
+				}
+
+				return nil
+			}
+
+			if dryRun {
+				diff, errD := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+					A:        difflib.SplitLines(string(data)),
+					B:        difflib.SplitLines(string(out)),
+					FromFile: *cfgFile,
+					ToFile:   *cfgFile + " (version " + migrate.CurrentVersion + ")",
+					Context:  3,
+				})
+				if errD != nil {
+					return errors.E(op, errD)
+				}
+
+				_, _ = fmt.Fprint(cmd.OutOrStdout(), diff)
+
+				return nil
+			}
+
+			err = os.WriteFile(*cfgFile, out, fi.Mode().Perm())
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			if silent == nil || !*silent {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s: migrated to version %s\n", *cfgFile, migrate.CurrentVersion)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the changes as a unified diff instead of rewriting the file")
+
+	return cmd
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSecretKey(t *testing.T) {
+	for key, want := range map[string]bool{
+		"password":       true,
+		"sasl_password":  true,
+		"pass":           true,
+		"passwd":         true,
+		"client_secret":  true,
+		"clientSecret":   true,
+		"auth-token":     true,
+		"bearer.token":   true,
+		"dsn":            true,
+		"credentials":    true,
+		"api_key":        true,
+		"apiKey":         true,
+		"apikey":         true,
+		"aws_access_key": true,
+		"private_key":    true,
+		"PRIVATE_KEY":    true,
+		"passive":        false,
+		"bypass":         false,
+		"max_tokens":     false,
+		"tokenizer":      false,
+		"key":            false,
+		"public_key":     false,
+		"compass":        false,
+		"secretary":      false,
+	} {
+		assert.Equal(t, want, isSecretKey(key), key)
+	}
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/roadrunner-server/errors"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/loader"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func newValidateCommand(cfgFile *string, override *[]string, silent *bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the configuration file against the bundled JSON schema",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			const op = errors.Op("config_validate")
+
+			if cfgFile == nil {
+				return errors.E(op, errors.Str("no configuration file provided"))
+			}
+
+			var flags []string
+			if override != nil {
+				flags = *override
+			}
+
+			c, err := loader.Load(*cfgFile, flags)
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			validator, err := schema.NewValidator()
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			loc := newLocator(c.Files(), flags)
+
+			settings := c.Settings()
+
+			violations, err := validator.Validate(settings)
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			if len(violations) == 0 {
+				if silent == nil || !*silent {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s: configuration is valid\n", *cfgFile)
+				}
+
+				return nil
+			}
+
+			for _, vl := range violations {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s: %s: %s\n", loc.locate(vl.Path), renderPath(settings, vl.Path), c.Redact(vl.Message))
+			}
+
+			return errors.Errorf("%s: %d schema violation(s) found", *cfgFile, len(violations))
+		},
+	}
+}
+
+// renderPath renders the location of a value in the YAML form, e.g. http.middleware[0].
+func renderPath(doc any, path []string) string {
+	if len(path) == 0 {
+		return "<root>"
+	}
+
+	var sb strings.Builder
+	for _, p := range path {
+		switch t := doc.(type) {
+		case []any:
+			sb.WriteString("[" + p + "]")
+			if idx, err := strconv.Atoi(p); err == nil && idx >= 0 && idx < len(t) {
+				doc = t[idx]
+				continue
+			}
+			doc = nil
+		case map[string]any:
+			if sb.Len() > 0 {
+				sb.WriteByte('.')
+			}
+			sb.WriteString(p)
+			doc = t[p]
+		default:
+			if sb.Len() > 0 {
+				sb.WriteByte('.')
+			}
+			sb.WriteString(p)
+			doc = nil
+		}
+	}
+
+	return sb.String()
+}
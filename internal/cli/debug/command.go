@@ -0,0 +1,19 @@
+package debug
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates `debug` command.
+func NewCommand(cfgFile *string, override *[]string, silent *bool) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Diagnostics tools",
+	}
+
+	cmd.AddCommand(
+		newProfilesCommand(cfgFile, override, silent),
+	)
+
+	return cmd
+}
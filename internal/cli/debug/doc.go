@@ -0,0 +1,4 @@
+// Package debug implements the "debug" command with the diagnostics tools,
+// such as listing and exporting the profiles captured by the continuous
+// profiler.
+package debug
@@ -0,0 +1,175 @@
+package debug
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+	"github.com/roadrunner-server/errors"
+	"github.com/roadrunner-server/roadrunner/v2025/container"
+	dbg "github.com/roadrunner-server/roadrunner/v2025/internal/debug"
+	"github.com/spf13/cobra"
+)
+
+// the archive is written to stdout
+const stdout = "-"
+
+func newProfilesCommand(cfgFile *string, override *[]string, silent *bool) *cobra.Command {
+	var dir string
+
+	cmd := &cobra.Command{
+		Use:   "profiles",
+		Short: "List the profiles captured by the continuous profiler (endure.debug.profiler)",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			const op = errors.Op("debug_profiles")
+
+			path, err := profilesDir(cfgFile, override, dir)
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			profiles, err := dbg.ListProfiles(path)
+			if err != nil {
+				if os.IsNotExist(err) {
+					return errors.E(op, errors.Errorf("no profiles directory %s, is the profiler enabled (endure.debug.profiler.enabled)?", path))
+				}
+
+				return errors.E(op, err)
+			}
+
+			if len(profiles) == 0 {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "no profiles found in %s\n", path)
+				return nil
+			}
+
+			_ = renderProfiles(cmd.OutOrStdout(), profiles).Render()
+
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&dir, "dir", "", "profiles directory [endure.debug.profiler.dir]")
+
+	cmd.AddCommand(newExportCommand(cfgFile, override, silent, &dir))
+
+	return cmd
+}
+
+func newExportCommand(cfgFile *string, override *[]string, silent *bool, dir *string) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "export [profile...]",
+		Short: "Export the profiles (all by default) as a tar.gz archive",
+		Example: `  rr debug profiles export --output profiles.tar.gz
+  rr debug profiles export 20261018T030000.000Z_heap_heap.pb.gz --output - | tar -tz`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			const op = errors.Op("debug_profiles_export")
+
+			path, err := profilesDir(cfgFile, override, *dir)
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			if output == stdout {
+				return dbg.ExportProfiles(cmd.OutOrStdout(), path, args)
+			}
+
+			f, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			err = dbg.ExportProfiles(f, path, args)
+			if errC := f.Close(); err == nil {
+				err = errC
+			}
+
+			if err != nil {
+				_ = os.Remove(output)
+				return errors.E(op, err)
+			}
+
+			if !*silent {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "profiles exported to %s\n", output)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&output, "output", "rr-profiles.tar.gz", "archive path, - for stdout")
+
+	return cmd
+}
+
+// profilesDir returns the directory from the flag or the configuration, relative to the working directory.
+func profilesDir(cfgFile *string, override *[]string, dir string) (string, error) {
+	if dir != "" {
+		return dir, nil
+	}
+
+	if cfgFile == nil {
+		return "", errors.Str("no configuration file provided")
+	}
+
+	var flags []string
+	if override != nil {
+		flags = *override
+	}
+
+	cfg, err := container.NewConfig(*cfgFile, flags...)
+	if err != nil {
+		return "", err
+	}
+
+	return cfg.Debug.Profiler.Dir, nil
+}
+
+func renderProfiles(writer io.Writer, profiles []*dbg.Profile) *tablewriter.Table {
+	tw := tablewriter.NewTable(writer, tablewriter.WithConfig(tableConfig()))
+	tw.Header([]string{"Name", "Kind", "Reason", "Captured", "Size"})
+
+	for _, p := range profiles {
+		_ = tw.Append([]string{p.Name, p.Kind, p.Reason, p.Captured.Local().Format(time.DateTime), formatSize(p.Size)})
+	}
+
+	return tw
+}
+
+func formatSize(size int64) string {
+	const unit = 1024
+
+	if size < unit {
+		return strconv.FormatInt(size, 10) + " B"
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func tableConfig() tablewriter.Config {
+	return tablewriter.Config{
+		Header: tw.CellConfig{
+			Formatting: tw.CellFormatting{
+				AutoFormat: tw.On,
+				AutoWrap:   int(tw.Off),
+			},
+		},
+		MaxWidth: 150,
+		Row: tw.CellConfig{
+			Alignment: tw.CellAlignment{
+				Global: tw.AlignLeft,
+			},
+		},
+	}
+}
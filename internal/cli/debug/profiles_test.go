@@ -0,0 +1,109 @@
+package debug_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/roadrunner-server/roadrunner/v2025/internal/cli/debug"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	cpuProfile  = "20261018T030000.000Z_cpu_periodic.pb.gz"
+	heapProfile = "20261018T030500.000Z_heap_heap.pb.gz"
+)
+
+func runDebug(t *testing.T, cfg string, args ...string) (string, error) {
+	t.Helper()
+
+	silent := false
+	cmd := debug.NewCommand(&cfg, &[]string{}, &silent)
+
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+
+	return out.String(), err
+}
+
+// writeConfig writes the configuration with the profiles directory containing the profiles.
+func writeConfig(t *testing.T, names ...string) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	profiles := filepath.Join(dir, "profiles")
+	require.NoError(t, os.Mkdir(profiles, 0o750))
+
+	for _, name := range names {
+		require.NoError(t, os.WriteFile(filepath.Join(profiles, name), []byte("profile"), 0o600))
+	}
+
+	cfg := filepath.Join(dir, ".rr.yaml")
+	require.NoError(t, os.WriteFile(cfg, []byte("version: \"3\"\nendure:\n  debug:\n    profiler:\n      dir: "+profiles+"\n"), 0o600))
+
+	return cfg, profiles
+}
+
+func TestProfilesList(t *testing.T) {
+	cfg, _ := writeConfig(t, cpuProfile, heapProfile)
+
+	out, err := runDebug(t, cfg, "profiles")
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "CAPTURED")
+	assert.Contains(t, out, cpuProfile)
+	assert.Contains(t, out, heapProfile)
+	assert.Contains(t, out, "7 B")
+}
+
+func TestProfilesListEmpty(t *testing.T) {
+	cfg, profiles := writeConfig(t)
+
+	out, err := runDebug(t, cfg, "profiles")
+	require.NoError(t, err)
+	assert.Contains(t, out, "no profiles found in "+profiles)
+
+	_, err = runDebug(t, cfg, "profiles", "--dir", filepath.Join(profiles, "missing"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is the profiler enabled")
+}
+
+func TestProfilesExport(t *testing.T) {
+	cfg, _ := writeConfig(t, cpuProfile, heapProfile)
+	archive := filepath.Join(t.TempDir(), "profiles.tar.gz")
+
+	out, err := runDebug(t, cfg, "profiles", "export", heapProfile, "--output", archive)
+	require.NoError(t, err)
+	assert.Contains(t, out, "profiles exported to "+archive)
+
+	f, err := os.Open(archive)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+
+	gr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	h, err := tar.NewReader(gr).Next()
+	require.NoError(t, err)
+	assert.Equal(t, heapProfile, h.Name)
+}
+
+func TestProfilesExportMissing(t *testing.T) {
+	cfg, _ := writeConfig(t, cpuProfile)
+	archive := filepath.Join(t.TempDir(), "profiles.tar.gz")
+
+	_, err := runDebug(t, cfg, "profiles", "export", heapProfile, "--output", archive)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not found")
+
+	// no partial archive is left
+	assert.NoFileExists(t, archive)
+}
@@ -10,7 +10,6 @@ import (
 )
 
 const (
-	listRPC    string = "jobs.List"
 	pauseRPC   string = "jobs.Pause"
 	destroyRPC string = "jobs.Destroy"
 	resumeRPC  string = "jobs.Resume"
@@ -23,12 +22,28 @@ func NewCommand(cfgFile *string, override *[]string, silent *bool) *cobra.Comman
 		destroyPipes bool
 		resumePipes  bool
 		listPipes    bool
+		dryRun       bool
+		yes          bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "jobs",
 		Short: "Jobs pipelines manipulation",
-		RunE: func(_ *cobra.Command, args []string) error {
+		Long: `Jobs pipelines manipulation.
+
+The pipelines are selected by the names, the glob patterns (tmp-*) or the regular expressions
+between slashes (/^tmp-[0-9]+$/), resolved against the pipelines declared on the server.
+
+The pipelines named like the subcommands (list, push, ...) are passed after --.`,
+		Example: `  rr jobs --pause emails,events
+  rr jobs --resume 'tmp-*'
+  rr jobs --destroy '/^tmp-[0-9]+$/' --dry-run
+  rr jobs --destroy 'tmp-*' --yes
+  rr jobs --pause -- list`,
+		// the pipelines are passed as the arguments of the command with the subcommands, the arguments after -- are
+		// never taken for a subcommand
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			const op = errors.Op("jobs_command")
 
 			if cfgFile == nil {
@@ -42,33 +57,53 @@ func NewCommand(cfgFile *string, override *[]string, silent *bool) *cobra.Comman
 
 			defer func() { _ = client.Close() }()
 
+			var (
+				action string
+				usage  string
+			)
+
 			switch {
 			case pausePipes:
-				if len(args) == 0 {
-					return errors.Str("incorrect command usage, should be: rr jobs --pause pipe1,pipe2")
-				}
-				split := strings.Split(strings.Trim(args[0], " "), ",")
-
-				return pause(client, split, silent)
+				action, usage = "paused", "rr jobs --pause pipe1,pipe2"
 			case destroyPipes:
-				if len(args) == 0 {
-					return errors.Str("incorrect command usage, should be: rr jobs --destroy pipe1,pipe2")
-				}
-				split := strings.Split(strings.Trim(args[0], " "), ",")
-
-				return destroy(client, split, silent)
+				action, usage = "destroyed", "rr jobs --destroy pipe1,pipe2"
 			case resumePipes:
-				if len(args) == 0 {
-					return errors.Str("incorrect command usage, should be: rr jobs --resume pipe1,pipe2")
-				}
-				split := strings.Split(strings.Trim(args[0], " "), ",")
-
-				return resume(client, split, silent)
+				action, usage = "resumed", "rr jobs --resume pipe1,pipe2"
 			case listPipes:
-				return list(client)
+				return list(cmd.OutOrStdout(), client)
 			default:
 				return errors.Str("command should be in form of: `rr jobs --<destroy/resume/pause> pipe1,pipe2`")
 			}
+
+			patterns := splitPatterns(args)
+			if len(patterns) == 0 {
+				return errors.Errorf("incorrect command usage, should be: %s", usage)
+			}
+
+			pipelines, err := selectPipelines(client, patterns)
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			if dryRun {
+				printDryRun(cmd.OutOrStdout(), action, pipelines)
+				return nil
+			}
+
+			switch {
+			case pausePipes:
+				return pause(cmd.OutOrStdout(), client, pipelines, silent)
+			case resumePipes:
+				return resume(cmd.OutOrStdout(), client, pipelines, silent)
+			default:
+				if !yes {
+					if err = confirm(cmd.InOrStdin(), cmd.OutOrStdout(), pipelines); err != nil {
+						return errors.E(op, err)
+					}
+				}
+
+				return destroy(cmd.OutOrStdout(), client, pipelines, silent)
+			}
 		},
 	}
 
@@ -77,6 +112,29 @@ func NewCommand(cfgFile *string, override *[]string, silent *bool) *cobra.Comman
 	cmd.Flags().BoolVar(&destroyPipes, "destroy", false, "destroy pipelines")
 	cmd.Flags().BoolVar(&resumePipes, "resume", false, "resume pipelines")
 	cmd.Flags().BoolVar(&listPipes, "list", false, "list pipelines")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show the pipelines which would be affected, without changing them")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "destroy the pipelines without the confirmation")
+
+	// `rr jobs --pause list` runs the list subcommand, which doesn't know the action flags
+	cmd.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		if c != cmd && strings.HasPrefix(err.Error(), "unknown flag: --") {
+			for _, action := range []string{"pause", "resume", "destroy"} {
+				if strings.HasSuffix(err.Error(), "--"+action) {
+					return errors.Errorf("%v: to select the pipeline named %q, pass it after --: rr jobs --%s -- %s", err, c.Name(), action, c.Name())
+				}
+			}
+		}
+
+		return err
+	})
+
+	cmd.AddCommand(
+		newPushCommand(cfgFile, override, silent),
+		newListCommand(cfgFile, override),
+		newStatsCommand(cfgFile, override),
+		newDrainCommand(cfgFile, override, silent),
+		newDeclareCommand(cfgFile, override, silent),
+	)
 
 	return cmd
 }
@@ -0,0 +1,248 @@
+package jobs
+
+import (
+	"encoding/json"
+	stderr "errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	jobsv1 "github.com/roadrunner-server/api-go/v6/jobs/v1"
+	"github.com/roadrunner-server/errors"
+	internalRpc "github.com/roadrunner-server/roadrunner/v2025/internal/rpc"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/schema"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+const declareRPC string = "jobs.Declare"
+
+func newDeclareCommand(cfgFile *string, override *[]string, silent *bool) *cobra.Command {
+	var (
+		file    string
+		driver  string
+		options []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "declare [pipeline...]",
+		Short: "Declare pipelines on the running server, from the flags or from a YAML file",
+		Example: `  rr jobs declare emails --driver amqp --option queue=emails --option priority=5
+  rr jobs declare --file pipelines.yaml
+  rr jobs declare emails --file pipelines.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			const op = errors.Op("jobs_declare")
+
+			if cfgFile == nil {
+				return errors.E(op, errors.Str("no configuration file provided"))
+			}
+
+			var (
+				pipelines map[string]map[string]any
+				err       error
+			)
+
+			switch {
+			case file != "" && (driver != "" || len(options) > 0):
+				return errors.E(op, errors.Str("--file can't be used together with --driver and --option"))
+			case file != "":
+				pipelines, err = readPipelines(file, args)
+			default:
+				if len(args) != 1 || driver == "" {
+					return errors.E(op, errors.Str("incorrect command usage, should be: rr jobs declare <pipeline> --driver <driver> [--option key=value]"))
+				}
+
+				var def map[string]any
+				def, err = pipelineFromFlags(driver, options)
+				pipelines = map[string]map[string]any{args[0]: def}
+			}
+
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			names := make([]string, 0, len(pipelines))
+			for name := range pipelines {
+				names = append(names, name)
+			}
+
+			sort.Strings(names)
+
+			// all the pipelines are validated before any of them is declared
+			validator, err := schema.NewValidator()
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			var problems []string
+			for _, name := range names {
+				violations, errV := validator.ValidatePipeline(pipelines[name])
+				switch {
+				case stderr.Is(errV, schema.ErrNoPipelineSchema):
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s: %v, the pipeline is not validated\n", name, errV)
+				case errV != nil:
+					return errors.E(op, errV)
+				}
+
+				for _, vl := range violations {
+					problems = append(problems, fmt.Sprintf("%s: %s", strings.Join(append([]string{name}, vl.Path...), "."), vl.Message))
+				}
+			}
+
+			if len(problems) > 0 {
+				return errors.E(op, errors.Errorf("invalid pipeline definition:\n%s", strings.Join(problems, "\n")))
+			}
+
+			var flags []string
+			if override != nil {
+				flags = *override
+			}
+
+			client, err := internalRpc.NewClient(*cfgFile, flags)
+			if err != nil {
+				return err
+			}
+
+			defer func() { _ = client.Close() }()
+
+			for _, name := range names {
+				req, err := declareRequest(name, pipelines[name])
+				if err != nil {
+					return errors.E(op, err)
+				}
+
+				err = client.Call(declareRPC, req, &jobsv1.Empty{})
+				if err != nil {
+					return errors.E(op, errors.Errorf("%s: %v", name, err))
+				}
+
+				if silent == nil || !*silent {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "declared: %s (%s)\n", name, pipelines[name]["driver"])
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&file, "file", "", "YAML file with the pipelines, the same shape as the jobs.pipelines section")
+	cmd.Flags().StringVar(&driver, "driver", "", "pipeline driver, e.g. amqp, kafka, memory")
+	cmd.Flags().StringArrayVar(&options, "option", nil, "driver option in the key=value form, can be repeated")
+
+	return cmd
+}
+
+// readPipelines reads the pipelines from the file, when the names are passed only these pipelines are returned.
+func readPipelines(file string, names []string) (map[string]map[string]any, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var pipelines map[string]map[string]any
+	if err = yaml.Unmarshal(data, &pipelines); err != nil {
+		return nil, errors.Errorf("%s: %v", file, err)
+	}
+
+	if len(pipelines) == 0 {
+		return nil, errors.Errorf("%s: no pipelines found", file)
+	}
+
+	if len(names) == 0 {
+		return pipelines, nil
+	}
+
+	res := make(map[string]map[string]any, len(names))
+	for _, name := range names {
+		def, ok := pipelines[name]
+		if !ok {
+			return nil, errors.Errorf("%s: pipeline not found: %s", file, name)
+		}
+
+		res[name] = def
+	}
+
+	return res, nil
+}
+
+// pipelineFromFlags builds the definition, the values are typed like in the YAML file.
+func pipelineFromFlags(driver string, options []string) (map[string]any, error) {
+	config := make(map[string]any, len(options))
+
+	for _, o := range options {
+		key, value, ok := strings.Cut(o, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, errors.Errorf("invalid option %q, should be key=value", o)
+		}
+
+		var typed any
+		if err := yaml.Unmarshal([]byte(value), &typed); err != nil || typed == nil {
+			typed = value
+		}
+
+		config[key] = typed
+	}
+
+	def := map[string]any{"driver": driver}
+	if len(config) > 0 {
+		def["config"] = config
+	}
+
+	return def, nil
+}
+
+// declareRequest flattens the definition: the jobs plugin expects the driver options as strings on the top level,
+// the nested values (e.g. kafka producer options) are passed as JSON.
+func declareRequest(name string, def map[string]any) (*jobsv1.DeclareRequest, error) {
+	pipeline := map[string]string{"name": name}
+
+	config, _ := def["config"].(map[string]any)
+	for k, v := range config {
+		s, err := stringify(v)
+		if err != nil {
+			return nil, errors.Errorf("%s: config.%s: %v", name, k, err)
+		}
+
+		pipeline[k] = s
+	}
+
+	for k, v := range def {
+		if k == "config" {
+			continue
+		}
+
+		s, err := stringify(v)
+		if err != nil {
+			return nil, errors.Errorf("%s: %s: %v", name, k, err)
+		}
+
+		pipeline[k] = s
+	}
+
+	return &jobsv1.DeclareRequest{Pipeline: pipeline}, nil
+}
+
+func stringify(v any) (string, error) {
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case bool:
+		return strconv.FormatBool(t), nil
+	case int:
+		return strconv.Itoa(t), nil
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), nil
+	case nil:
+		return "", nil
+	default:
+		data, err := json.Marshal(t)
+		if err != nil {
+			return "", err
+		}
+
+		return string(data), nil
+	}
+}
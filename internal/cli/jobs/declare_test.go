@@ -0,0 +1,109 @@
+package jobs_test
+
+import (
+	"sync"
+	"testing"
+
+	jobsv1 "github.com/roadrunner-server/api-go/v6/jobs/v1"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/rpctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDeclare records the declared pipelines.
+type fakeDeclare struct {
+	mu       sync.Mutex
+	declared []map[string]string
+}
+
+func (f *fakeDeclare) Declare(req *jobsv1.DeclareRequest, _ *jobsv1.Empty) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.declared = append(f.declared, req.GetPipeline())
+
+	return nil
+}
+
+func TestDeclareFromFlags(t *testing.T) {
+	fd := &fakeDeclare{}
+	cfg := rpctest.Config(t, map[string]any{"jobs": fd})
+
+	out, err := runJobs(t, cfg, "", "declare", "emails", "--driver", "amqp", "--option", "queue=emails", "--option", "priority=5", "--option", "durable=true")
+	require.NoError(t, err)
+	assert.Equal(t, "declared: emails (amqp)\n", out)
+
+	require.Len(t, fd.declared, 1)
+	assert.Equal(t, map[string]string{
+		"name":     "emails",
+		"driver":   "amqp",
+		"queue":    "emails",
+		"priority": "5",
+		"durable":  "true",
+	}, fd.declared[0])
+}
+
+func TestDeclareFromFile(t *testing.T) {
+	fd := &fakeDeclare{}
+	cfg := rpctest.Config(t, map[string]any{"jobs": fd})
+
+	out, err := runJobs(t, cfg, "", "declare", "--file", "test/pipelines.yaml")
+	require.NoError(t, err)
+
+	// the memory driver schema is not bundled
+	assert.Contains(t, out, "warning: local:")
+	assert.Contains(t, out, "declared: emails (amqp)\ndeclared: events (kafka)\ndeclared: local (memory)\n")
+
+	require.Len(t, fd.declared, 3)
+	assert.Equal(t, "emails", fd.declared[0]["name"])
+	assert.Equal(t, "direct", fd.declared[0]["exchange_type"])
+
+	events := fd.declared[1]
+	assert.Equal(t, "kafka", events["driver"])
+	assert.Equal(t, "true", events["auto_create_topics_enable"])
+	assert.JSONEq(t, `{"max_message_bytes": 1000, "required_acks": "LeaderAck"}`, events["producer_options"])
+	assert.JSONEq(t, `{"topics": ["events"]}`, events["consumer_options"])
+
+	assert.Equal(t, "100", fd.declared[2]["prefetch"])
+}
+
+func TestDeclareSelected(t *testing.T) {
+	fd := &fakeDeclare{}
+	cfg := rpctest.Config(t, map[string]any{"jobs": fd})
+
+	_, err := runJobs(t, cfg, "", "declare", "emails", "--file", "test/pipelines.yaml")
+	require.NoError(t, err)
+
+	require.Len(t, fd.declared, 1)
+	assert.Equal(t, "emails", fd.declared[0]["name"])
+
+	_, err = runJobs(t, cfg, "", "declare", "orders", "--file", "test/pipelines.yaml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pipeline not found: orders")
+}
+
+func TestDeclareInvalid(t *testing.T) {
+	fd := &fakeDeclare{}
+	cfg := rpctest.Config(t, map[string]any{"jobs": fd})
+
+	_, err := runJobs(t, cfg, "", "declare", "--file", "test/pipelines-invalid.yaml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid pipeline definition")
+	assert.Contains(t, err.Error(), "emails.config.priority:")
+	assert.Contains(t, err.Error(), "no_such_option")
+
+	_, err = runJobs(t, cfg, "", "declare", "emails", "--driver", "amqp", "--option", "prefetch=many")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "emails.config.prefetch:")
+
+	_, err = runJobs(t, cfg, "", "declare", "emails")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "incorrect command usage")
+
+	_, err = runJobs(t, cfg, "", "declare", "--file", "test/pipelines.yaml", "--driver", "amqp")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "can't be used together")
+
+	// nothing is declared when the validation fails
+	assert.Empty(t, fd.declared)
+}
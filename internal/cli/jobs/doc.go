@@ -1,3 +1,5 @@
 // Package jobs implements the "jobs" command for managing job pipelines,
-// supporting pause, resume, destroy, and list operations via RPC.
+// supporting pause, resume, destroy, drain, list and stats operations,
+// declaring pipelines and pushing jobs via RPC. The pipelines are selected
+// by names, glob patterns or regular expressions.
 package jobs
@@ -0,0 +1,205 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/rpc"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	jobsv1 "github.com/roadrunner-server/api-go/v6/jobs/v1"
+	"github.com/roadrunner-server/api-plugins/v6/jobs"
+	"github.com/roadrunner-server/errors"
+	internalRpc "github.com/roadrunner-server/roadrunner/v2025/internal/rpc"
+	"github.com/spf13/cobra"
+)
+
+func newDrainCommand(cfgFile *string, override *[]string, silent *bool) *cobra.Command {
+	var (
+		timeout      time.Duration
+		interval     time.Duration
+		destroyPipes bool
+		dryRun       bool
+		yes          bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "drain <pipeline|pattern> [pipeline|pattern...]",
+		Short: "Pause the pipelines, wait for the in-flight jobs and optionally destroy the pipelines",
+		Example: `  rr jobs drain emails events --timeout 5m
+  rr jobs drain 'tmp-*' --destroy --dry-run`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			const op = errors.Op("jobs_drain")
+
+			if cfgFile == nil {
+				return errors.E(op, errors.Str("no configuration file provided"))
+			}
+
+			if timeout <= 0 || interval <= 0 {
+				return errors.E(op, errors.Str("--timeout and --interval should be positive"))
+			}
+
+			var flags []string
+			if override != nil {
+				flags = *override
+			}
+
+			client, err := internalRpc.NewClient(*cfgFile, flags)
+			if err != nil {
+				return err
+			}
+
+			defer func() { _ = client.Close() }()
+
+			states, err := pipelineStates(client)
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			names := make([]string, 0, len(states))
+			for _, st := range states {
+				names = append(names, st.Pipeline)
+			}
+
+			pipelines, err := matchPipelines(names, splitPatterns(args))
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			if dryRun {
+				action := "drained"
+				if destroyPipes {
+					action = "drained and destroyed"
+				}
+
+				printDryRun(cmd.OutOrStdout(), action, pipelines)
+
+				return nil
+			}
+
+			// ask before anything is paused, the aborted drain leaves the pipelines untouched
+			if destroyPipes && !yes {
+				if err = confirm(cmd.InOrStdin(), cmd.OutOrStdout(), pipelines); err != nil {
+					return errors.E(op, err)
+				}
+			}
+
+			err = client.Call(pauseRPC, &jobsv1.Pipelines{Pipelines: pipelines}, &jobsv1.Empty{})
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			var progress io.Writer = io.Discard
+			if silent == nil || !*silent {
+				progress = cmd.OutOrStdout()
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			err = waitDrained(ctx, progress, client, pipelines, timeout, interval)
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			if !destroyPipes {
+				return nil
+			}
+
+			resp := &jobsv1.Pipelines{}
+			err = client.Call(destroyRPC, &jobsv1.Pipelines{Pipelines: pipelines}, resp)
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			_, _ = fmt.Fprintf(progress, "destroyed: %s\n", strings.Join(resp.GetPipelines(), ", "))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", time.Minute, "maximum time to wait for the in-flight jobs")
+	cmd.Flags().DurationVar(&interval, "interval", time.Second, "polling interval")
+	cmd.Flags().BoolVar(&destroyPipes, "destroy", false, "destroy the pipelines once they are drained")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show the pipelines which would be affected, without changing them")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "destroy the pipelines without the confirmation")
+
+	return cmd
+}
+
+// waitDrained polls the pipelines until both the active and the reserved jobs reach zero: the drivers report the jobs
+// being processed differently, e.g. memory and amqp never report the reserved ones. The delayed jobs are only reported.
+func waitDrained(ctx context.Context, w io.Writer, client *rpc.Client, pipelines []string, timeout, interval time.Duration) error {
+	start := time.Now()
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	tt := time.NewTicker(interval)
+	defer tt.Stop()
+
+	// rewrite the progress line on the terminal
+	prefix, suffix := "", "\n"
+	if isTerminal(w) {
+		prefix, suffix = "\r\033[K", ""
+	}
+
+	// finish the rewritten progress line
+	done := func() {
+		if suffix == "" {
+			_, _ = io.WriteString(w, "\n")
+		}
+	}
+
+	for {
+		states, err := pipelineStates(client)
+		if err != nil {
+			return err
+		}
+
+		inFlight, delayed, details := drainProgress(states, pipelines)
+		_, _ = fmt.Fprintf(w, "%s%d in-flight job(s), %d delayed [%s], %s elapsed%s",
+			prefix, inFlight, delayed, details, time.Since(start).Round(time.Second), suffix)
+
+		if inFlight == 0 {
+			done()
+			_, _ = fmt.Fprintf(w, "drained: %s\n", strings.Join(pipelines, ", "))
+
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			done()
+			return errors.Errorf("drain interrupted, %d job(s) still in flight", inFlight)
+		case <-deadline.C:
+			done()
+			return errors.Errorf("drain timed out after %s, %d job(s) still in flight", timeout, inFlight)
+		case <-tt.C:
+		}
+	}
+}
+
+// drainProgress sums the in-flight (active and reserved) and the delayed jobs of the pipelines.
+func drainProgress(states []*jobs.State, pipelines []string) (int64, int64, string) {
+	var inFlight, delayed int64
+
+	details := make([]string, 0, len(pipelines))
+	for _, st := range states {
+		if !slices.Contains(pipelines, st.Pipeline) {
+			continue
+		}
+
+		inFlight += st.Active + st.Reserved
+		delayed += st.Delayed
+		details = append(details, fmt.Sprintf("%s: %d active, %d reserved", st.Pipeline, st.Active, st.Reserved))
+	}
+
+	return inFlight, delayed, strings.Join(details, ", ")
+}
@@ -0,0 +1,170 @@
+package jobs_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	jobsv1 "github.com/roadrunner-server/api-go/v6/jobs/v1"
+	"github.com/roadrunner-server/api-plugins/v6/jobs"
+	jobsCmd "github.com/roadrunner-server/roadrunner/v2025/internal/cli/jobs"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/rpctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDrain serves both the informer and the jobs RPC: every informer call finishes one of the reserved jobs, then one
+// of the active jobs of the paused pipeline unless stuck is set.
+type fakeDrain struct {
+	mu        sync.Mutex
+	active    int64
+	reserved  int64
+	stuck     bool
+	paused    []string
+	destroyed []string
+}
+
+func (f *fakeDrain) Jobs(_ string, out *[]*jobs.State) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.paused) > 0 && !f.stuck {
+		switch {
+		case f.reserved > 0:
+			f.reserved--
+		case f.active > 0:
+			f.active--
+		}
+	}
+
+	*out = []*jobs.State{
+		{Pipeline: "emails", Driver: "amqp", Active: f.active, Reserved: f.reserved, Delayed: 4, Ready: len(f.paused) == 0},
+		{Pipeline: "audit", Driver: "memory", Reserved: 100},
+	}
+
+	return nil
+}
+
+func (f *fakeDrain) Pause(req *jobsv1.Pipelines, _ *jobsv1.Empty) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.paused = append(f.paused, req.GetPipelines()...)
+
+	return nil
+}
+
+func (f *fakeDrain) Destroy(req *jobsv1.Pipelines, resp *jobsv1.Pipelines) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.destroyed = append(f.destroyed, req.GetPipelines()...)
+	resp.Pipelines = req.GetPipelines()
+
+	return nil
+}
+
+func startDrain(t *testing.T, fd *fakeDrain) string {
+	t.Helper()
+
+	return rpctest.Config(t, map[string]any{"informer": fd, "jobs": fd})
+}
+
+func TestDrain(t *testing.T) {
+	fd := &fakeDrain{active: 2, reserved: 3}
+	cfg := startDrain(t, fd)
+
+	out, err := runJobs(t, cfg, "", "drain", "emails", "--interval", "10ms")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"emails"}, fd.paused)
+	assert.Empty(t, fd.destroyed)
+	assert.Contains(t, out, "4 in-flight job(s), 4 delayed [emails: 2 active, 2 reserved]")
+	assert.Contains(t, out, "1 in-flight job(s), 4 delayed [emails: 1 active, 0 reserved]")
+	assert.Contains(t, out, "0 in-flight job(s)")
+	assert.Contains(t, out, "drained: emails\n")
+}
+
+func TestDrainDestroy(t *testing.T) {
+	fd := &fakeDrain{active: 2, reserved: 1}
+	cfg := startDrain(t, fd)
+
+	out, err := runJobs(t, cfg, "", "drain", "emails", "--destroy", "--yes", "--interval", "10ms")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"emails"}, fd.destroyed)
+	assert.Contains(t, out, "0 in-flight job(s)")
+	assert.Contains(t, out, "destroyed: emails\n")
+}
+
+func TestDrainNilSilent(t *testing.T) {
+	fd := &fakeDrain{active: 1}
+	cfg := startDrain(t, fd)
+
+	cmd := jobsCmd.NewCommand(&cfg, &[]string{}, nil)
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{"drain", "emails", "--interval", "10ms"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "0 in-flight job(s)")
+}
+
+// the drivers like memory and amqp report the jobs being processed as active only
+func TestDrainActiveOnly(t *testing.T) {
+	fd := &fakeDrain{active: 3, stuck: true}
+	cfg := startDrain(t, fd)
+
+	_, err := runJobs(t, cfg, "", "drain", "emails", "--destroy", "-y", "--timeout", "50ms", "--interval", "10ms")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "drain timed out after 50ms, 3 job(s) still in flight")
+	assert.Empty(t, fd.destroyed)
+}
+
+func TestDrainTimeout(t *testing.T) {
+	fd := &fakeDrain{reserved: 3, stuck: true}
+	cfg := startDrain(t, fd)
+
+	_, err := runJobs(t, cfg, "", "drain", "emails", "--destroy", "-y", "--timeout", "50ms", "--interval", "10ms")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "drain timed out after 50ms, 3 job(s) still in flight")
+
+	// a pipeline which is not drained is never destroyed
+	assert.Empty(t, fd.destroyed)
+}
+
+func TestDrainUnknownPipeline(t *testing.T) {
+	fd := &fakeDrain{}
+	cfg := startDrain(t, fd)
+
+	_, err := runJobs(t, cfg, "", "drain", "emails", "orders")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no pipelines match: orders")
+	assert.Empty(t, fd.paused)
+}
+
+func TestDrainDestroyAborted(t *testing.T) {
+	fd := &fakeDrain{reserved: 1}
+	cfg := startDrain(t, fd)
+
+	out, err := runJobs(t, cfg, "no\n", "drain", "emails", "--destroy", "--interval", "10ms")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "aborted, no pipelines were destroyed")
+	assert.Contains(t, out, "the following pipelines will be destroyed:\n  - emails\n")
+
+	// the aborted drain doesn't pause the pipelines
+	assert.Empty(t, fd.paused)
+	assert.Empty(t, fd.destroyed)
+}
+
+func TestDrainDryRun(t *testing.T) {
+	fd := &fakeDrain{}
+	cfg := startDrain(t, fd)
+
+	out, err := runJobs(t, cfg, "", "drain", "*", "--destroy", "--dry-run")
+	require.NoError(t, err)
+
+	assert.Equal(t, "dry run, the following pipelines would be drained and destroyed:\n  - audit\n  - emails\n", out)
+	assert.Empty(t, fd.paused)
+	assert.Empty(t, fd.destroyed)
+}
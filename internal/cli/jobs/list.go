@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"io"
+	"net/rpc"
+	"sort"
+
+	"github.com/roadrunner-server/api-plugins/v6/jobs"
+	"github.com/roadrunner-server/errors"
+	internalRpc "github.com/roadrunner-server/roadrunner/v2025/internal/rpc"
+	"github.com/spf13/cobra"
+)
+
+const (
+	informerJobs string = "informer.Jobs"
+	// the name of the jobs plugin in the informer
+	jobsPluginName string = "jobs"
+)
+
+func newListCommand(cfgFile *string, override *[]string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the pipelines with their driver, queue and counters",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			const op = errors.Op("jobs_list")
+
+			if cfgFile == nil {
+				return errors.E(op, errors.Str("no configuration file provided"))
+			}
+
+			var flags []string
+			if override != nil {
+				flags = *override
+			}
+
+			client, err := internalRpc.NewClient(*cfgFile, flags)
+			if err != nil {
+				return err
+			}
+
+			defer func() { _ = client.Close() }()
+
+			return list(cmd.OutOrStdout(), client)
+		},
+	}
+}
+
+func list(w io.Writer, client *rpc.Client) error {
+	states, err := pipelineStates(client)
+	if err != nil {
+		return err
+	}
+
+	_ = renderStates(w, states).Render()
+
+	return nil
+}
+
+// pipelineStates returns the state of every pipeline sorted by the pipeline name.
+func pipelineStates(client *rpc.Client) ([]*jobs.State, error) {
+	var states []*jobs.State
+
+	err := client.Call(informerJobs, jobsPluginName, &states)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Pipeline < states[j].Pipeline
+	})
+
+	return states, nil
+}
@@ -0,0 +1,410 @@
+package jobs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	jobsv1 "github.com/roadrunner-server/api-go/v6/jobs/v1"
+	"github.com/roadrunner-server/errors"
+	internalRpc "github.com/roadrunner-server/roadrunner/v2025/internal/rpc"
+	"github.com/spf13/cobra"
+)
+
+const (
+	pushRPC      string = "jobs.Push"
+	pushBatchRPC string = "jobs.PushBatch"
+	// stdin is used as the payload source
+	stdinPath string = "-"
+)
+
+// pushOptions are the job options from the flags, in the batch mode they are the defaults for every line.
+type pushOptions struct {
+	job         string
+	id          string
+	headers     []string
+	priority    int64
+	delay       time.Duration
+	autoAck     bool
+	topic       string
+	payloadFile string
+	batch       bool
+	batchSize   int
+}
+
+// batchLine is a single NDJSON line of the batch mode, the missing fields are taken from the flags.
+type batchLine struct {
+	Job      string          `json:"job"`
+	ID       string          `json:"id"`
+	Payload  json.RawMessage `json:"payload"`
+	Headers  map[string]any  `json:"headers"`
+	Priority *int64          `json:"priority"`
+	Delay    string          `json:"delay"`
+	AutoAck  *bool           `json:"auto_ack"`
+	Topic    string          `json:"topic"`
+}
+
+func newPushCommand(cfgFile *string, override *[]string, silent *bool) *cobra.Command {
+	opts := &pushOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "push <pipeline> [payload|-]",
+		Short: "Push a job (or a batch of NDJSON jobs) to the pipeline",
+		Example: `  rr jobs push emails '{"to":"user@example.com"}' --job App/Jobs/SendEmail -H tenant=acme
+  rr jobs push emails --payload-file job.json --delay 30s
+  cat jobs.ndjson | rr jobs push emails --batch`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			const op = errors.Op("jobs_push")
+
+			if cfgFile == nil {
+				return errors.E(op, errors.Str("no configuration file provided"))
+			}
+
+			pipeline := args[0]
+
+			var payloadArg *string
+			if len(args) == 2 {
+				payloadArg = &args[1]
+			}
+
+			if payloadArg != nil && opts.payloadFile != "" {
+				return errors.E(op, errors.Str("the payload argument and --payload-file can't be used together"))
+			}
+
+			if opts.batch && payloadArg != nil && *payloadArg != stdinPath {
+				return errors.E(op, errors.Str("the batch mode reads the jobs from --payload-file or stdin"))
+			}
+
+			if opts.batchSize <= 0 {
+				return errors.E(op, errors.Errorf("--batch-size should be positive, got %d", opts.batchSize))
+			}
+
+			if err := checkDelay(opts.delay); err != nil {
+				return errors.E(op, errors.Errorf("--delay: %v", err))
+			}
+
+			quiet := silent != nil && *silent
+
+			headers, err := parseHeaders(opts.headers)
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			var flags []string
+			if override != nil {
+				flags = *override
+			}
+
+			if opts.batch {
+				return pushBatch(cmd, *cfgFile, flags, pipeline, headers, opts, quiet)
+			}
+
+			payload, err := readPayload(cmd.InOrStdin(), payloadArg, opts.payloadFile)
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			job := newJob(pipeline, headers, opts)
+			job.Payload = payload
+
+			client, err := internalRpc.NewClient(*cfgFile, flags)
+			if err != nil {
+				return err
+			}
+
+			defer func() { _ = client.Close() }()
+
+			err = client.Call(pushRPC, &jobsv1.PushRequest{Job: job}, &jobsv1.Empty{})
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			if !quiet {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "pushed job %s to [%s]\n", job.GetId(), pipeline)
+			}
+
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&opts.job, "job", "", "job name (default: the pipeline name)")
+	f.StringVar(&opts.id, "id", "", "job ID (default: random UUID)")
+	f.StringArrayVarP(&opts.headers, "header", "H", nil, "job header in the key=value form, can be repeated")
+	f.Int64Var(&opts.priority, "priority", 0, "job priority, 0 inherits the pipeline priority")
+	f.DurationVar(&opts.delay, "delay", 0, "delay before the job is available to the workers in whole seconds, e.g. 30s")
+	f.BoolVar(&opts.autoAck, "auto-ack", false, "acknowledge the job before it's processed")
+	f.StringVar(&opts.topic, "topic", "", "topic, used by the drivers like Kafka")
+	f.StringVar(&opts.payloadFile, "payload-file", "", "read the payload (or the NDJSON batch) from the file, - for stdin")
+	f.BoolVar(&opts.batch, "batch", false, "read NDJSON jobs, one per line, and push them with the batch calls")
+	f.IntVar(&opts.batchSize, "batch-size", 100, "maximum number of jobs in a single batch call")
+
+	return cmd
+}
+
+// pushBatch reads the NDJSON jobs and pushes them in chunks of the batch size.
+func pushBatch(cmd *cobra.Command, cfgFile string, flags []string, pipeline string, headers map[string]*jobsv1.HeaderValue, opts *pushOptions, silent bool) error {
+	const op = errors.Op("jobs_push_batch")
+
+	src, closeFn, err := openSource(cmd.InOrStdin(), opts.payloadFile)
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	defer closeFn()
+
+	batch, err := readBatch(src, pipeline, headers, opts)
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	if len(batch) == 0 {
+		return errors.E(op, errors.Str("no jobs found in the batch input"))
+	}
+
+	client, err := internalRpc.NewClient(cfgFile, flags)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = client.Close() }()
+
+	for start := 0; start < len(batch); start += opts.batchSize {
+		end := min(start+opts.batchSize, len(batch))
+
+		err = client.Call(pushBatchRPC, &jobsv1.PushBatchRequest{Jobs: batch[start:end]}, &jobsv1.Empty{})
+		if err != nil {
+			return errors.E(op, errors.Errorf("pushed %d of %d jobs: %v", start, len(batch), err))
+		}
+	}
+
+	if !silent {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "pushed %d jobs to [%s]\n", len(batch), pipeline)
+	}
+
+	return nil
+}
+
+// newJob creates a job from the flags.
+func newJob(pipeline string, headers map[string]*jobsv1.HeaderValue, opts *pushOptions) *jobsv1.Job {
+	name := opts.job
+	if name == "" {
+		name = pipeline
+	}
+
+	id := opts.id
+	if id == "" {
+		id = uuid.NewString()
+	}
+
+	return &jobsv1.Job{
+		Job:     name,
+		Id:      id,
+		Headers: headers,
+		Options: &jobsv1.Options{
+			Priority: opts.priority,
+			Pipeline: pipeline,
+			Delay:    int64(opts.delay / time.Second),
+			AutoAck:  opts.autoAck,
+			Topic:    opts.topic,
+		},
+	}
+}
+
+// checkDelay rejects the delays the jobs can't carry: the delay is passed in seconds, 500ms would become 0.
+func checkDelay(delay time.Duration) error {
+	switch {
+	case delay < 0:
+		return errors.Errorf("should not be negative, got %s", delay)
+	case delay%time.Second != 0:
+		return errors.Errorf("should be a whole number of seconds, got %s", delay)
+	default:
+		return nil
+	}
+}
+
+// parseHeaders converts the key=value pairs, the repeated keys are collected into a single header.
+func parseHeaders(pairs []string) (map[string]*jobsv1.HeaderValue, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	headers := make(map[string]*jobsv1.HeaderValue, len(pairs))
+	for _, p := range pairs {
+		key, value, ok := strings.Cut(p, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, errors.Errorf("invalid header %q, should be key=value", p)
+		}
+
+		key = strings.TrimSpace(key)
+		if _, ok = headers[key]; !ok {
+			headers[key] = &jobsv1.HeaderValue{}
+		}
+
+		headers[key].Value = append(headers[key].Value, value)
+	}
+
+	return headers, nil
+}
+
+// readPayload returns the payload from the argument, the file or stdin (when the argument or the file is "-").
+func readPayload(stdin io.Reader, arg *string, file string) ([]byte, error) {
+	switch {
+	case arg != nil && *arg != stdinPath:
+		return []byte(*arg), nil
+	case arg != nil, file == stdinPath:
+		return io.ReadAll(stdin)
+	case file != "":
+		return os.ReadFile(file)
+	default:
+		return nil, nil
+	}
+}
+
+func openSource(stdin io.Reader, file string) (io.Reader, func(), error) {
+	if file == "" || file == stdinPath {
+		return stdin, func() {}, nil
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return f, func() { _ = f.Close() }, nil
+}
+
+// readBatch reads the NDJSON jobs, the empty lines are skipped.
+func readBatch(r io.Reader, pipeline string, headers map[string]*jobsv1.HeaderValue, opts *pushOptions) ([]*jobsv1.Job, error) {
+	var batch []*jobsv1.Job
+
+	sc := bufio.NewScanner(r)
+	// jobs payloads might be quite big
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for n := 1; sc.Scan(); n++ {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		job, err := parseBatchLine(line, pipeline, headers, opts)
+		if err != nil {
+			return nil, errors.Errorf("line %d: %v", n, err)
+		}
+
+		batch = append(batch, job)
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+func parseBatchLine(line []byte, pipeline string, headers map[string]*jobsv1.HeaderValue, opts *pushOptions) (*jobsv1.Job, error) {
+	bl := &batchLine{}
+
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(bl); err != nil {
+		return nil, err
+	}
+
+	lineOpts := *opts
+	// every job in the batch needs its own ID
+	lineOpts.id = bl.ID
+
+	if bl.Job != "" {
+		lineOpts.job = bl.Job
+	}
+
+	if bl.Priority != nil {
+		lineOpts.priority = *bl.Priority
+	}
+
+	if bl.AutoAck != nil {
+		lineOpts.autoAck = *bl.AutoAck
+	}
+
+	if bl.Topic != "" {
+		lineOpts.topic = bl.Topic
+	}
+
+	if bl.Delay != "" {
+		delay, err := time.ParseDuration(bl.Delay)
+		if err != nil {
+			return nil, errors.Errorf("invalid delay: %v", err)
+		}
+
+		if err = checkDelay(delay); err != nil {
+			return nil, errors.Errorf("invalid delay: %v", err)
+		}
+
+		lineOpts.delay = delay
+	}
+
+	jobHeaders, err := mergeHeaders(headers, bl.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	job := newJob(pipeline, jobHeaders, &lineOpts)
+
+	// a JSON string is pushed as is, any other JSON value is pushed as its JSON representation
+	if len(bl.Payload) > 0 {
+		var s string
+		if json.Unmarshal(bl.Payload, &s) == nil {
+			job.Payload = []byte(s)
+		} else {
+			job.Payload = bl.Payload
+		}
+	}
+
+	return job, nil
+}
+
+// mergeHeaders adds the line headers (a string or a list of strings per key) to the headers from the flags.
+func mergeHeaders(base map[string]*jobsv1.HeaderValue, line map[string]any) (map[string]*jobsv1.HeaderValue, error) {
+	if len(line) == 0 {
+		return base, nil
+	}
+
+	res := make(map[string]*jobsv1.HeaderValue, len(base)+len(line))
+	for k, v := range base {
+		res[k] = &jobsv1.HeaderValue{Value: append([]string(nil), v.Value...)}
+	}
+
+	for k, v := range line {
+		var values []string
+
+		switch t := v.(type) {
+		case string:
+			values = []string{t}
+		case []any:
+			for _, item := range t {
+				s, ok := item.(string)
+				if !ok {
+					return nil, errors.Errorf("header %q: values should be strings", k)
+				}
+
+				values = append(values, s)
+			}
+		default:
+			return nil, errors.Errorf("header %q: should be a string or a list of strings", k)
+		}
+
+		res[k] = &jobsv1.HeaderValue{Value: values}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,201 @@
+package jobs_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	jobsv1 "github.com/roadrunner-server/api-go/v6/jobs/v1"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/cli/jobs"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/rpctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeJobs records the requests of the jobs plugin RPC.
+type fakeJobs struct {
+	mu      sync.Mutex
+	pushed  []*jobsv1.Job
+	batches int
+}
+
+func (f *fakeJobs) Push(req *jobsv1.PushRequest, _ *jobsv1.Empty) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.pushed = append(f.pushed, req.Job)
+
+	return nil
+}
+
+func (f *fakeJobs) PushBatch(req *jobsv1.PushBatchRequest, _ *jobsv1.Empty) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.pushed = append(f.pushed, req.GetJobs()...)
+	f.batches++
+
+	return nil
+}
+
+func runJobs(t *testing.T, cfg string, stdin string, args ...string) (string, error) {
+	t.Helper()
+
+	return runJobsContext(t.Context(), t, cfg, stdin, args...)
+}
+
+func runJobsContext(ctx context.Context, t *testing.T, cfg string, stdin string, args ...string) (string, error) {
+	t.Helper()
+
+	silent := false
+	cmd := jobs.NewCommand(&cfg, &[]string{}, &silent)
+
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetArgs(args)
+
+	err := cmd.ExecuteContext(ctx)
+
+	return out.String(), err
+}
+
+func TestPush(t *testing.T) {
+	fj := &fakeJobs{}
+	cfg := rpctest.Config(t, map[string]any{"jobs": fj})
+
+	out, err := runJobs(t, cfg, "",
+		"push", "emails", `{"to":"user@example.com"}`,
+		"--job", "App/Jobs/SendEmail",
+		"--id", "job-1",
+		"-H", "tenant=acme", "-H", "tenant=other", "-H", "trace=1",
+		"--priority", "5",
+		"--delay", "1m30s",
+		"--auto-ack",
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "pushed job job-1 to [emails]\n", out)
+
+	require.Len(t, fj.pushed, 1)
+	job := fj.pushed[0]
+	assert.Equal(t, "App/Jobs/SendEmail", job.Job)
+	assert.Equal(t, "job-1", job.Id)
+	assert.Equal(t, `{"to":"user@example.com"}`, string(job.Payload))
+	assert.Equal(t, []string{"acme", "other"}, job.Headers["tenant"].Value)
+	assert.Equal(t, []string{"1"}, job.Headers["trace"].Value)
+	assert.Equal(t, "emails", job.Options.Pipeline)
+	assert.Equal(t, int64(5), job.Options.Priority)
+	assert.Equal(t, int64(90), job.Options.Delay)
+	assert.True(t, job.Options.AutoAck)
+}
+
+// the silent flag is optional, the same as the configuration overrides
+func TestPushNilSilent(t *testing.T) {
+	fj := &fakeJobs{}
+	cfg := rpctest.Config(t, map[string]any{"jobs": fj})
+
+	cmd := jobs.NewCommand(&cfg, nil, nil)
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{"push", "emails", "payload"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "pushed job")
+	require.Len(t, fj.pushed, 1)
+}
+
+func TestPushDefaults(t *testing.T) {
+	fj := &fakeJobs{}
+	cfg := rpctest.Config(t, map[string]any{"jobs": fj})
+
+	_, err := runJobs(t, cfg, "from stdin", "push", "emails", "-")
+	require.NoError(t, err)
+
+	payload := filepath.Join(t.TempDir(), "job.json")
+	require.NoError(t, os.WriteFile(payload, []byte("from file"), 0o600))
+
+	_, err = runJobs(t, cfg, "", "push", "emails", "--payload-file", payload)
+	require.NoError(t, err)
+
+	require.Len(t, fj.pushed, 2)
+	assert.Equal(t, "from stdin", string(fj.pushed[0].Payload))
+	assert.Equal(t, "from file", string(fj.pushed[1].Payload))
+
+	// job name defaults to the pipeline, the ID is generated
+	assert.Equal(t, "emails", fj.pushed[0].Job)
+	assert.NotEmpty(t, fj.pushed[0].Id)
+	assert.NotEqual(t, fj.pushed[0].Id, fj.pushed[1].Id)
+	assert.Zero(t, fj.pushed[0].Options.Priority)
+}
+
+func TestPushBatch(t *testing.T) {
+	fj := &fakeJobs{}
+	cfg := rpctest.Config(t, map[string]any{"jobs": fj})
+
+	input := `{"job": "a", "payload": "plain text"}
+
+{"payload": {"nested": true}, "headers": {"tenant": ["x", "y"]}, "priority": 1, "delay": "5s", "id": "fixed"}
+{"job": "c", "auto_ack": true}
+`
+
+	out, err := runJobs(t, cfg, input, "push", "emails", "--batch", "--batch-size", "2", "-H", "source=cli", "--job", "default")
+	require.NoError(t, err)
+	assert.Equal(t, "pushed 3 jobs to [emails]\n", out)
+
+	assert.Equal(t, 2, fj.batches)
+	require.Len(t, fj.pushed, 3)
+
+	assert.Equal(t, "a", fj.pushed[0].Job)
+	assert.Equal(t, "plain text", string(fj.pushed[0].Payload))
+	assert.Equal(t, []string{"cli"}, fj.pushed[0].Headers["source"].Value)
+
+	assert.Equal(t, "default", fj.pushed[1].Job)
+	assert.Equal(t, "fixed", fj.pushed[1].Id)
+	assert.JSONEq(t, `{"nested": true}`, string(fj.pushed[1].Payload))
+	assert.Equal(t, []string{"x", "y"}, fj.pushed[1].Headers["tenant"].Value)
+	assert.Equal(t, []string{"cli"}, fj.pushed[1].Headers["source"].Value)
+	assert.Equal(t, int64(1), fj.pushed[1].Options.Priority)
+	assert.Equal(t, int64(5), fj.pushed[1].Options.Delay)
+
+	assert.True(t, fj.pushed[2].Options.AutoAck)
+	assert.Empty(t, fj.pushed[2].Payload)
+
+	// every job gets its own ID
+	assert.NotEqual(t, fj.pushed[0].Id, fj.pushed[2].Id)
+}
+
+func TestPushErrors(t *testing.T) {
+	cfg := rpctest.Config(t, map[string]any{"jobs": &fakeJobs{}})
+
+	cases := []struct {
+		name    string
+		stdin   string
+		args    []string
+		wantErr string
+	}{
+		{name: "no pipeline", args: []string{"push"}, wantErr: "accepts between 1 and 2 arg(s)"},
+		{name: "bad header", args: []string{"push", "p", "x", "-H", "novalue"}, wantErr: `invalid header "novalue"`},
+		{name: "payload twice", args: []string{"push", "p", "x", "--payload-file", "f"}, wantErr: "can't be used together"},
+		{name: "batch with payload", args: []string{"push", "p", "x", "--batch"}, wantErr: "reads the jobs from --payload-file or stdin"},
+		{name: "empty batch", args: []string{"push", "p", "--batch"}, wantErr: "no jobs found"},
+		{name: "bad line", stdin: "{}\n{\"unknown\": 1}\n", args: []string{"push", "p", "--batch"}, wantErr: "line 2:"},
+		{name: "bad delay", stdin: `{"delay": "soon"}`, args: []string{"push", "p", "--batch"}, wantErr: "invalid delay"},
+		{name: "sub-second delay", args: []string{"push", "p", "x", "--delay", "500ms"}, wantErr: "--delay: should be a whole number of seconds, got 500ms"},
+		{name: "negative delay", args: []string{"push", "p", "x", "--delay", "-1s"}, wantErr: "--delay: should not be negative"},
+		{name: "sub-second line delay", stdin: `{"delay": "1.5s"}`, args: []string{"push", "p", "--batch"}, wantErr: "line 1: invalid delay: should be a whole number of seconds"},
+		{name: "bad batch size", args: []string{"push", "p", "--batch", "--batch-size", "0"}, wantErr: "--batch-size should be positive"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runJobs(t, cfg, tt.stdin, tt.args...)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
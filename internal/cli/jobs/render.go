@@ -2,14 +2,88 @@ package jobs
 
 import (
 	"io"
+	"strconv"
 
+	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
 	"github.com/olekukonko/tablewriter/tw"
+	"github.com/roadrunner-server/api-plugins/v6/jobs"
+)
+
+const (
+	statusReady  string = "READY"
+	statusPaused string = "PAUSED/STOPPED"
 )
 
 // JobsCommandsRender uses console renderer to show jobs
 func renderPipelines(writer io.Writer, pipelines []string) *tablewriter.Table {
-	cfg := tablewriter.Config{
+	tw := tablewriter.NewTable(writer, tablewriter.WithConfig(tableConfig()))
+	tw.Header([]string{"Pipeline(s)"})
+
+	for i := range pipelines {
+		_ = tw.Append([]string{pipelines[i]})
+	}
+
+	return tw
+}
+
+// renderStates renders the full state of the pipelines.
+func renderStates(writer io.Writer, states []*jobs.State) *tablewriter.Table {
+	tw := tablewriter.NewTable(writer, tablewriter.WithConfig(tableConfig()))
+	tw.Header([]string{"Status", "Pipeline", "Driver", "Queue", "Priority", "Active", "Delayed", "Reserved", "Error"})
+
+	for _, st := range states {
+		_ = tw.Append([]string{
+			renderReady(st.Ready),
+			st.Pipeline,
+			st.Driver,
+			st.Queue,
+			strconv.FormatUint(st.Priority, 10),
+			strconv.FormatInt(st.Active, 10),
+			strconv.FormatInt(st.Delayed, 10),
+			strconv.FormatInt(st.Reserved, 10),
+			st.ErrorMessage,
+		})
+	}
+
+	return tw
+}
+
+// renderStats renders the counters of the pipelines and, when the rates are known, the backlog growth.
+func renderStats(writer io.Writer, states []*jobs.State, r *rates) *tablewriter.Table {
+	tw := tablewriter.NewTable(writer, tablewriter.WithConfig(tableConfig()))
+	tw.Header([]string{"Status", "Pipeline", "Driver", "Active", "Delayed", "Reserved", "Backlog", "Backlog/sec", "Consumed/sec"})
+
+	for _, st := range states {
+		growth, consumed := "-", "-"
+		if r != nil {
+			if g, ok := r.growth[st.Pipeline]; ok {
+				growth = renderGrowth(g)
+			}
+
+			if c, ok := r.consumedBy[st.Pipeline]; ok {
+				consumed = strconv.FormatFloat(c, 'f', 2, 64)
+			}
+		}
+
+		_ = tw.Append([]string{
+			renderReady(st.Ready),
+			st.Pipeline,
+			st.Driver,
+			strconv.FormatInt(st.Active, 10),
+			strconv.FormatInt(st.Delayed, 10),
+			strconv.FormatInt(st.Reserved, 10),
+			strconv.FormatInt(backlog(st), 10),
+			growth,
+			consumed,
+		})
+	}
+
+	return tw
+}
+
+func tableConfig() tablewriter.Config {
+	return tablewriter.Config{
 		Header: tw.CellConfig{
 			Formatting: tw.CellFormatting{
 				AutoFormat: tw.On,
@@ -23,12 +97,25 @@ func renderPipelines(writer io.Writer, pipelines []string) *tablewriter.Table {
 			},
 		},
 	}
-	tw := tablewriter.NewTable(writer, tablewriter.WithConfig(cfg))
-	tw.Header([]string{"Pipeline(s)"})
+}
 
-	for i := range pipelines {
-		_ = tw.Append([]string{pipelines[i]})
+func renderReady(ready bool) string {
+	if ready {
+		return color.GreenString(statusReady)
 	}
 
-	return tw
+	return color.YellowString(statusPaused)
+}
+
+func renderGrowth(g float64) string {
+	s := strconv.FormatFloat(g, 'f', 2, 64)
+
+	switch {
+	case g > 0:
+		return color.RedString("+" + s)
+	case g < 0:
+		return color.GreenString(s)
+	default:
+		return s
+	}
 }
@@ -0,0 +1,140 @@
+package jobs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/rpc"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	jobsv1 "github.com/roadrunner-server/api-go/v6/jobs/v1"
+	"github.com/roadrunner-server/errors"
+)
+
+const listRPC string = "jobs.List"
+
+// splitPatterns splits the comma-separated arguments, e.g. `pipe1,pipe2 'tmp-*'`.
+func splitPatterns(args []string) []string {
+	var patterns []string
+
+	for _, arg := range args {
+		for p := range strings.SplitSeq(arg, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				patterns = append(patterns, p)
+			}
+		}
+	}
+
+	return patterns
+}
+
+// selectPipelines resolves the patterns against the pipelines declared on the server.
+func selectPipelines(client *rpc.Client, patterns []string) ([]string, error) {
+	resp := &jobsv1.Pipelines{}
+
+	err := client.Call(listRPC, &jobsv1.Empty{}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return matchPipelines(resp.GetPipelines(), patterns)
+}
+
+// matchPipelines returns the sorted pipelines matching any of the patterns. A pattern is a glob (`prod-*`), or a
+// regular expression between slashes (`/^tmp-[0-9]+$/`). Every pattern should match at least one pipeline.
+func matchPipelines(pipelines, patterns []string) ([]string, error) {
+	var (
+		selected []string
+		missing  []string
+	)
+
+	for _, p := range patterns {
+		match, err := matcher(p)
+		if err != nil {
+			return nil, err
+		}
+
+		found := false
+		for _, name := range pipelines {
+			if !match(name) {
+				continue
+			}
+
+			found = true
+			if !slices.Contains(selected, name) {
+				selected = append(selected, name)
+			}
+		}
+
+		if !found {
+			missing = append(missing, p)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, errors.Errorf("no pipelines match: %s", strings.Join(missing, ", "))
+	}
+
+	slices.Sort(selected)
+
+	return selected, nil
+}
+
+func matcher(pattern string) (func(string) bool, error) {
+	if len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nil, errors.Errorf("invalid regular expression %s: %v", pattern, err)
+		}
+
+		return re.MatchString, nil
+	}
+
+	// validate the glob once, path.Match reports the bad pattern only when it reaches the broken part
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, errors.Errorf("invalid pattern %s: %v", pattern, err)
+	}
+
+	return func(name string) bool {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	}, nil
+}
+
+// printDryRun shows the pipelines which would be affected by the action.
+func printDryRun(w io.Writer, action string, pipelines []string) {
+	_, _ = fmt.Fprintf(w, "dry run, the following pipelines would be %s:\n", action)
+	for _, p := range pipelines {
+		_, _ = fmt.Fprintf(w, "  - %s\n", p)
+	}
+}
+
+// confirm asks the user to type `yes` before the pipelines are destroyed. A non-interactive stdin is refused, so the
+// scripts have to pass --yes explicitly.
+func confirm(in io.Reader, out io.Writer, pipelines []string) error {
+	if f, ok := in.(*os.File); ok && !isTerminal(f) {
+		return errors.Str("refusing to destroy the pipelines without a confirmation, stdin is not a terminal: use --yes")
+	}
+
+	_, _ = fmt.Fprintf(out, "the following pipelines will be destroyed:\n")
+	for _, p := range pipelines {
+		_, _ = fmt.Fprintf(out, "  - %s\n", p)
+	}
+
+	_, _ = fmt.Fprint(out, "type 'yes' to continue: ")
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	if strings.TrimSpace(answer) != "yes" {
+		return errors.Str("aborted, no pipelines were destroyed")
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package jobs_test
+
+import (
+	"sync"
+	"testing"
+
+	jobsv1 "github.com/roadrunner-server/api-go/v6/jobs/v1"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/rpctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePipelines struct {
+	mu        sync.Mutex
+	paused    []string
+	resumed   []string
+	destroyed []string
+}
+
+func (f *fakePipelines) List(_ *jobsv1.Empty, resp *jobsv1.Pipelines) error {
+	resp.Pipelines = []string{"emails", "tmp-1", "tmp-2", "tmp-old", "events", "list"}
+	return nil
+}
+
+func (f *fakePipelines) Pause(req *jobsv1.Pipelines, _ *jobsv1.Empty) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.paused = append(f.paused, req.GetPipelines()...)
+
+	return nil
+}
+
+func (f *fakePipelines) Resume(req *jobsv1.Pipelines, _ *jobsv1.Empty) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.resumed = append(f.resumed, req.GetPipelines()...)
+
+	return nil
+}
+
+func (f *fakePipelines) Destroy(req *jobsv1.Pipelines, resp *jobsv1.Pipelines) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.destroyed = append(f.destroyed, req.GetPipelines()...)
+	resp.Pipelines = req.GetPipelines()
+
+	return nil
+}
+
+func TestSelectPatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{name: "names", args: []string{"emails,events"}, expected: []string{"emails", "events"}},
+		{name: "glob", args: []string{"tmp-*"}, expected: []string{"tmp-1", "tmp-2", "tmp-old"}},
+		{name: "regexp", args: []string{"/^tmp-[0-9]+$/"}, expected: []string{"tmp-1", "tmp-2"}},
+		{name: "several args", args: []string{"tmp-?", "emails", "tmp-1"}, expected: []string{"emails", "tmp-1", "tmp-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp := &fakePipelines{}
+			cfg := rpctest.Config(t, map[string]any{"jobs": fp})
+
+			_, err := runJobs(t, cfg, "", append([]string{"--pause"}, tt.args...)...)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, fp.paused)
+		})
+	}
+}
+
+// the pipelines named like the subcommands are passed after --
+func TestSelectSubcommandName(t *testing.T) {
+	fp := &fakePipelines{}
+	cfg := rpctest.Config(t, map[string]any{"jobs": fp})
+
+	_, err := runJobs(t, cfg, "", "--pause", "list")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `to select the pipeline named "list", pass it after --: rr jobs --pause -- list`)
+	assert.Empty(t, fp.paused)
+
+	_, err = runJobs(t, cfg, "", "--pause", "--", "list", "emails")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"emails", "list"}, fp.paused)
+}
+
+func TestSelectNoMatch(t *testing.T) {
+	fp := &fakePipelines{}
+	cfg := rpctest.Config(t, map[string]any{"jobs": fp})
+
+	_, err := runJobs(t, cfg, "", "--resume", "emails,orders,/^prod/")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no pipelines match: orders, /^prod/")
+	assert.Empty(t, fp.resumed)
+
+	_, err = runJobs(t, cfg, "", "--resume", "/[/")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid regular expression /[/")
+}
+
+func TestDryRun(t *testing.T) {
+	fp := &fakePipelines{}
+	cfg := rpctest.Config(t, map[string]any{"jobs": fp})
+
+	out, err := runJobs(t, cfg, "", "--destroy", "tmp-*", "--dry-run")
+	require.NoError(t, err)
+
+	assert.Equal(t, "dry run, the following pipelines would be destroyed:\n  - tmp-1\n  - tmp-2\n  - tmp-old\n", out)
+	assert.Empty(t, fp.destroyed)
+}
+
+func TestDestroyConfirmation(t *testing.T) {
+	tests := []struct {
+		name      string
+		stdin     string
+		args      []string
+		destroyed []string
+		err       string
+	}{
+		{name: "confirmed", stdin: "yes\n", args: []string{"tmp-*"}, destroyed: []string{"tmp-1", "tmp-2", "tmp-old"}},
+		{name: "declined", stdin: "y\n", args: []string{"tmp-*"}, err: "aborted, no pipelines were destroyed"},
+		{name: "no answer", stdin: "", args: []string{"tmp-*"}, err: "aborted, no pipelines were destroyed"},
+		{name: "skipped", stdin: "", args: []string{"tmp-*", "--yes"}, destroyed: []string{"tmp-1", "tmp-2", "tmp-old"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp := &fakePipelines{}
+			cfg := rpctest.Config(t, map[string]any{"jobs": fp})
+
+			out, err := runJobs(t, cfg, tt.stdin, append([]string{"--destroy"}, tt.args...)...)
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.destroyed, fp.destroyed)
+
+			if tt.stdin != "" {
+				assert.Contains(t, out, "the following pipelines will be destroyed:\n  - tmp-1\n  - tmp-2\n  - tmp-old\n")
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/rpc"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/roadrunner-server/api-plugins/v6/jobs"
+	"github.com/roadrunner-server/errors"
+	"github.com/roadrunner-server/informer/v6"
+	internalRpc "github.com/roadrunner-server/roadrunner/v2025/internal/rpc"
+	"github.com/spf13/cobra"
+)
+
+const informerWorkers string = "informer.Workers"
+
+// snapshot is the state of the pipelines and the exec counters of the jobs workers at some moment.
+type snapshot struct {
+	at     time.Time
+	states []*jobs.State
+	// pid -> number of executions, nil when the workers are not available
+	execs map[int64]uint64
+}
+
+// rates are derived from two successive snapshots.
+type rates struct {
+	// pipeline -> backlog (active + delayed) change per second
+	growth map[string]float64
+	// pipeline -> decrease of the queued jobs (active + delayed + reserved) per second. The counters are gauges, so
+	// the jobs pushed during the interval hide the same number of the consumed ones.
+	consumedBy map[string]float64
+	// jobs processed by all the jobs workers per second, the workers are shared between the pipelines
+	consumed    float64
+	hasConsumed bool
+}
+
+func newStatsCommand(cfgFile *string, override *[]string) *cobra.Command {
+	var (
+		watch    bool
+		interval time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show the pipelines counters, with --watch also the consumed jobs/sec and the backlog growth per pipeline",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			const op = errors.Op("jobs_stats")
+
+			if cfgFile == nil {
+				return errors.E(op, errors.Str("no configuration file provided"))
+			}
+
+			if interval <= 0 {
+				return errors.E(op, errors.Errorf("--interval should be positive, got %s", interval))
+			}
+
+			var flags []string
+			if override != nil {
+				flags = *override
+			}
+
+			client, err := internalRpc.NewClient(*cfgFile, flags)
+			if err != nil {
+				return err
+			}
+
+			defer func() { _ = client.Close() }()
+
+			out := cmd.OutOrStdout()
+
+			if !watch {
+				cur, err := takeSnapshot(client)
+				if err != nil {
+					return errors.E(op, err)
+				}
+
+				_ = renderStats(out, cur.states, nil).Render()
+
+				return nil
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return watchStats(ctx, out, client, interval)
+		},
+	}
+
+	cmd.Flags().BoolVar(&watch, "watch", false, "poll the stats periodically and show the throughput")
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "polling interval for --watch")
+
+	return cmd
+}
+
+// watchStats renders the stats every interval until the context is canceled.
+func watchStats(ctx context.Context, w io.Writer, client *rpc.Client, interval time.Duration) error {
+	tt := time.NewTicker(interval)
+	defer tt.Stop()
+
+	var prev *snapshot
+
+	for {
+		cur, err := takeSnapshot(client)
+		if err != nil {
+			return err
+		}
+
+		if isTerminal(w) {
+			// move the cursor home and clear the screen
+			_, _ = io.WriteString(w, "\033[H\033[2J")
+		}
+
+		var r *rates
+		if prev != nil {
+			r = calculate(prev, cur)
+		}
+
+		_, _ = fmt.Fprintf(w, "%s (every %s)\n", cur.at.Format(time.DateTime), interval)
+		_ = renderStats(w, cur.states, r).Render()
+
+		if r != nil && r.hasConsumed {
+			_, _ = fmt.Fprintf(w, "consumed: %.2f jobs/sec (all pipelines)\n", r.consumed)
+		}
+
+		prev = cur
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-tt.C:
+		}
+	}
+}
+
+func takeSnapshot(client *rpc.Client) (*snapshot, error) {
+	states, err := pipelineStates(client)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &snapshot{at: time.Now(), states: states}
+
+	// the throughput is optional, the stats are still useful without it
+	list := &informer.WorkerList{}
+	if err = client.Call(informerWorkers, jobsPluginName, &list); err == nil {
+		s.execs = make(map[int64]uint64, len(list.Workers))
+		for _, wrk := range list.Workers {
+			s.execs[wrk.Pid] = wrk.NumExecs
+		}
+	}
+
+	return s, nil
+}
+
+// calculate returns the rates between the snapshots.
+func calculate(prev, cur *snapshot) *rates {
+	r := &rates{
+		growth:     make(map[string]float64, len(cur.states)),
+		consumedBy: make(map[string]float64, len(cur.states)),
+	}
+
+	elapsed := cur.at.Sub(prev.at).Seconds()
+	if elapsed <= 0 {
+		return r
+	}
+
+	before := make(map[string]*jobs.State, len(prev.states))
+	for _, st := range prev.states {
+		before[st.Pipeline] = st
+	}
+
+	for _, st := range cur.states {
+		b, ok := before[st.Pipeline]
+		if !ok {
+			continue
+		}
+
+		r.growth[st.Pipeline] = float64(backlog(st)-backlog(b)) / elapsed
+		r.consumedBy[st.Pipeline] = float64(max(queued(b)-queued(st), 0)) / elapsed
+	}
+
+	if prev.execs != nil && cur.execs != nil {
+		var processed uint64
+		for pid, execs := range cur.execs {
+			// the new (or restarted) worker counts from zero
+			if old, ok := prev.execs[pid]; ok && execs >= old {
+				processed += execs - old
+				continue
+			}
+
+			processed += execs
+		}
+
+		r.consumed = float64(processed) / elapsed
+		r.hasConsumed = true
+	}
+
+	return r
+}
+
+func backlog(st *jobs.State) int64 {
+	return st.Active + st.Delayed
+}
+
+// queued returns all the jobs of the pipeline which are not processed yet.
+func queued(st *jobs.State) int64 {
+	return st.Active + st.Delayed + st.Reserved
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
@@ -0,0 +1,126 @@
+package jobs_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/roadrunner-server/api-plugins/v6/jobs"
+	"github.com/roadrunner-server/informer/v6"
+	"github.com/roadrunner-server/pool/v2/state/process"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/rpctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeInformer grows the backlog of the emails pipeline, consumes 20 jobs of the audit pipeline and grows the exec
+// counter of the worker on every call.
+type fakeInformer struct {
+	mu    sync.Mutex
+	calls int64
+}
+
+func (f *fakeInformer) Jobs(plugin string, out *[]*jobs.State) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls++
+
+	*out = []*jobs.State{
+		{Pipeline: "emails", Driver: "amqp", Queue: "emails", Active: 10 * f.calls, Delayed: 1, Reserved: 2, Ready: true, Priority: 10},
+		{Pipeline: "audit", Driver: "memory", Queue: "audit", Reserved: 1000 - 20*f.calls, ErrorMessage: "connection refused"},
+	}
+
+	return nil
+}
+
+func (f *fakeInformer) Workers(plugin string, out *informer.WorkerList) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	out.Workers = []*process.State{{Pid: 1, NumExecs: uint64(5 * f.calls)}}
+
+	return nil
+}
+
+func TestList(t *testing.T) {
+	cfg := rpctest.Config(t, map[string]any{"informer": &fakeInformer{}})
+
+	for _, args := range [][]string{{"list"}, {"--list"}} {
+		out, err := runJobs(t, cfg, "", args...)
+		require.NoError(t, err)
+
+		lines := strings.Split(out, "\n")
+		require.Greater(t, len(lines), 5)
+
+		// sorted by the pipeline name
+		assert.Less(t, strings.Index(out, "audit"), strings.Index(out, "emails"))
+		assert.Contains(t, out, "amqp")
+		assert.Contains(t, out, "PRIORITY")
+		assert.Contains(t, out, "connection")
+		assert.Contains(t, out, "READY")
+		assert.Contains(t, out, "PAUSED/STOPPED")
+	}
+}
+
+func TestStats(t *testing.T) {
+	cfg := rpctest.Config(t, map[string]any{"informer": &fakeInformer{}})
+
+	out, err := runJobs(t, cfg, "", "stats")
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "BACKLOG / SEC")
+	// active + delayed
+	assert.Contains(t, out, "11")
+	assert.NotContains(t, out, "consumed")
+}
+
+func TestStatsWatch(t *testing.T) {
+	cfg := rpctest.Config(t, map[string]any{"informer": &fakeInformer{}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 350*time.Millisecond)
+	defer cancel()
+
+	res, err := runJobsContext(ctx, t, cfg, "", "stats", "--watch", "--interval", "100ms")
+	require.NoError(t, err)
+
+	assert.GreaterOrEqual(t, strings.Count(res, "(every 100ms)"), 2)
+	// the backlog grows by 10 jobs and the worker processes 5 jobs per ~100ms
+	assert.Contains(t, res, "consumed: ")
+	assert.Contains(t, res, "jobs/sec (all pipelines)")
+	assert.Contains(t, res, "+")
+
+	// the consumption of each pipeline (the last column) once there are two snapshots: the emails jobs are not
+	// consumed, the audit ones are
+	assert.Contains(t, res, "CONSUMED / SEC")
+	consumed := map[string][]string{}
+	for _, l := range strings.Split(res, "\n") {
+		cells := strings.Split(strings.Trim(l, "│ "), "│")
+		if len(cells) < 2 || strings.TrimSpace(cells[len(cells)-1]) == "-" {
+			continue
+		}
+
+		pipeline := strings.TrimSpace(cells[1])
+		consumed[pipeline] = append(consumed[pipeline], strings.TrimSpace(cells[len(cells)-1]))
+	}
+
+	require.NotEmpty(t, consumed["emails"])
+	for _, c := range consumed["emails"] {
+		assert.Equal(t, "0.00", c)
+	}
+
+	require.NotEmpty(t, consumed["audit"])
+	for _, c := range consumed["audit"] {
+		assert.NotEqual(t, "0.00", c)
+	}
+}
+
+func TestStatsInterval(t *testing.T) {
+	cfg := rpctest.Config(t, map[string]any{"informer": &fakeInformer{}})
+
+	_, err := runJobs(t, cfg, "", "stats", "--watch", "--interval", "0s")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--interval should be positive")
+}
@@ -1,13 +1,13 @@
 package jobs
 
 import (
+	"io"
 	"net/rpc"
-	"os"
 
 	jobsv1 "github.com/roadrunner-server/api-go/v6/jobs/v1"
 )
 
-func pause(client *rpc.Client, pause []string, silent *bool) error {
+func pause(w io.Writer, client *rpc.Client, pause []string, silent *bool) error {
 	pipes := &jobsv1.Pipelines{Pipelines: pause}
 	er := &jobsv1.Empty{}
 
@@ -17,13 +17,13 @@ func pause(client *rpc.Client, pause []string, silent *bool) error {
 	}
 
 	if !*silent {
-		_ = renderPipelines(os.Stdout, pause).Render()
+		_ = renderPipelines(w, pause).Render()
 	}
 
 	return nil
 }
 
-func resume(client *rpc.Client, resume []string, silent *bool) error {
+func resume(w io.Writer, client *rpc.Client, resume []string, silent *bool) error {
 	pipes := &jobsv1.Pipelines{Pipelines: resume}
 	er := &jobsv1.Empty{}
 
@@ -33,13 +33,13 @@ func resume(client *rpc.Client, resume []string, silent *bool) error {
 	}
 
 	if !*silent {
-		_ = renderPipelines(os.Stdout, resume).Render()
+		_ = renderPipelines(w, resume).Render()
 	}
 
 	return nil
 }
 
-func destroy(client *rpc.Client, destroy []string, silent *bool) error {
+func destroy(w io.Writer, client *rpc.Client, destroy []string, silent *bool) error {
 	pipes := &jobsv1.Pipelines{Pipelines: destroy}
 	resp := &jobsv1.Pipelines{}
 
@@ -49,22 +49,8 @@ func destroy(client *rpc.Client, destroy []string, silent *bool) error {
 	}
 
 	if !*silent {
-		_ = renderPipelines(os.Stdout, resp.GetPipelines()).Render()
+		_ = renderPipelines(w, resp.GetPipelines()).Render()
 	}
 
 	return nil
 }
-
-func list(client *rpc.Client) error {
-	resp := &jobsv1.Pipelines{}
-	er := &jobsv1.Empty{}
-
-	err := client.Call(listRPC, er, resp)
-	if err != nil {
-		return err
-	}
-
-	_ = renderPipelines(os.Stdout, resp.GetPipelines()).Render()
-
-	return nil
-}
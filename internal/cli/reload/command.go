@@ -0,0 +1,91 @@
+package reload
+
+import (
+	"cmp"
+	"log"
+	"os"
+	"syscall"
+
+	"github.com/roadrunner-server/errors"
+	"github.com/roadrunner-server/roadrunner/v2025/container"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/pidfile"
+	cfgreload "github.com/roadrunner-server/roadrunner/v2025/internal/reload"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates `reload` command.
+func NewCommand(cfgFile *string, override *[]string, pidFile *string, silent *bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reload",
+		Short: "Reload RoadRunner configuration",
+		Long: `Validate the configuration and send SIGHUP to the server from the pid file (-p flag or endure.pid_file).
+The invalid configuration is reported and the server is not signaled, it keeps running with the current configuration.
+
+The server compares the new configuration with the running one. When only the pool options (<plugin>.pool) or the
+endure options used by the server itself have changed, they are applied without restarting the plugins: the workers of
+the plugins with the changed pool options are reset, the workers are added or removed for the new pool sizes.
+Otherwise all the plugins are restarted, or with endure.restart_mode graceful or upgrade, a new process is started with
+the new configuration. When the plugins fail to start with the new configuration, the current one is restored.`,
+		RunE: func(*cobra.Command, []string) error {
+			const op = errors.Op("rr_reload")
+
+			if cfgFile == nil {
+				return errors.E(op, errors.Str("no configuration file provided"))
+			}
+
+			var flags []string
+			if override != nil {
+				flags = *override
+			}
+
+			// the server re-reads the same files, so the rejected reload is reported here rather than in its logs
+			next, err := cfgreload.Load(*cfgFile, flags)
+			if err != nil {
+				return errors.E(op, errors.Errorf("reload rejected: %v", err))
+			}
+
+			cfg, err := container.ParseConfig(next.Viper())
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			var flagPath string
+			if pidFile != nil {
+				flagPath = *pidFile
+			}
+
+			// the same precedence as in serve
+			path := cmp.Or(flagPath, cfg.PidFile, pidfile.DefaultPath)
+
+			pid, err := pidfile.Read(path)
+			if err != nil {
+				if os.IsNotExist(err) {
+					return errors.Errorf("%v, to create a pid file, you must run RR with the following options: './rr serve -p' or set endure.pid_file", err)
+				}
+
+				return errors.E(op, err)
+			}
+
+			// SIGHUP terminates the program which doesn't handle it, the PID from the stale pid file might be reused
+			if err = pidfile.Check(path, pid); err != nil {
+				return errors.E(op, err)
+			}
+
+			process, err := os.FindProcess(pid)
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			err = process.Signal(syscall.SIGHUP)
+			if err != nil {
+				return errors.E(op, errors.Errorf("failed to signal process with PID %d: %v", pid, err))
+			}
+
+			if silent == nil || !*silent {
+				log.Printf("configuration is valid, reload signal sent to PID %d", pid)
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,190 @@
+//go:build !windows
+
+package reload_test
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/roadrunner-server/roadrunner/v2025/internal/cli/reload"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/pidfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// the test binary plays the server: it locks the pid file like serve does and exits with 0 after SIGHUP
+const childEnv = "RR_RELOAD_TEST_CHILD"
+
+// the pid file of the fake server, locked until it exits
+var pidFile *pidfile.File //nolint:gochecknoglobals
+
+func TestMain(m *testing.M) {
+	if os.Getenv(childEnv) != "" {
+		var err error
+		if pidFile, err = pidfile.Create(".pid"); err != nil {
+			os.Exit(2)
+		}
+
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGHUP)
+		_, _ = os.Stdout.WriteString("ready\n")
+
+		select {
+		case <-sig:
+			os.Exit(0)
+		case <-time.After(time.Minute):
+			os.Exit(1)
+		}
+	}
+
+	os.Exit(m.Run())
+}
+
+// startServer starts the fake server with the pid file in the current directory and returns the exit status.
+func startServer(t *testing.T) <-chan error {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Env = append(os.Environ(), childEnv+"=1")
+
+	stdout, err := cmd.StdoutPipe()
+	require.NoError(t, err)
+	require.NoError(t, cmd.Start())
+
+	line := make([]byte, len("ready\n"))
+	_, err = stdout.Read(line)
+	require.NoError(t, err)
+	require.Equal(t, "ready\n", string(line))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+	})
+
+	return done
+}
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	cfg := filepath.Join(t.TempDir(), ".rr.yaml")
+	require.NoError(t, os.WriteFile(cfg, []byte(content), 0o600))
+
+	return cfg
+}
+
+func runReload(cfg string) error {
+	cmd := reload.NewCommand(&cfg, &[]string{}, toPtr(""), toPtr(true))
+	cmd.SetArgs([]string{})
+
+	return cmd.Execute()
+}
+
+func TestReloadSignalsServer(t *testing.T) {
+	t.Chdir(t.TempDir())
+	done := startServer(t)
+
+	require.NoError(t, runReload(writeConfig(t, "version: \"3\"\nrpc:\n  listen: tcp://127.0.0.1:6001\n")))
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second * 10):
+		t.Fatal("the server was not signaled")
+	}
+}
+
+func TestReloadInvalidConfig(t *testing.T) {
+	t.Chdir(t.TempDir())
+	done := startServer(t)
+
+	err := runReload(writeConfig(t, "version: \"3\"\nlogs:\n  level: verbose\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reload rejected")
+	assert.Contains(t, err.Error(), "logs.level")
+
+	// the server is not signaled and keeps running with the current configuration
+	select {
+	case err = <-done:
+		t.Fatalf("the server was signaled: %v", err)
+	case <-time.After(time.Millisecond * 300):
+	}
+}
+
+// the server is signaled when the silent flag is not set
+func TestReloadNilSilent(t *testing.T) {
+	t.Chdir(t.TempDir())
+	done := startServer(t)
+
+	cfg := writeConfig(t, "version: \"3\"\n")
+	cmd := reload.NewCommand(&cfg, nil, nil, nil)
+	cmd.SetArgs([]string{})
+	require.NoError(t, cmd.Execute())
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second * 10):
+		t.Fatal("the server was not signaled")
+	}
+}
+
+func TestReloadStalePid(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	// the PID of the exited process
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	require.NoError(t, cmd.Run())
+	require.NoError(t, os.WriteFile(".pid", []byte(strconv.Itoa(cmd.Process.Pid)), 0o600))
+
+	err := runReload(writeConfig(t, "version: \"3\"\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not running")
+}
+
+// the PID of another program is never signaled, its pid file is not locked
+func TestReloadReusedPid(t *testing.T) {
+	sleep, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("no sleep binary")
+	}
+
+	t.Chdir(t.TempDir())
+
+	other := exec.Command(sleep, "60")
+	require.NoError(t, other.Start())
+	t.Cleanup(func() {
+		_ = other.Process.Kill()
+		_ = other.Wait()
+	})
+	require.NoError(t, os.WriteFile(".pid", []byte(strconv.Itoa(other.Process.Pid)), 0o600))
+
+	err = runReload(writeConfig(t, "version: \"3\"\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), ".pid is not locked, PID")
+	assert.Contains(t, err.Error(), "belongs to another program")
+
+	assert.NoError(t, syscall.Kill(other.Process.Pid, 0))
+}
+
+func TestReloadNoPidFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	err := runReload(writeConfig(t, "version: \"3\"\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "./rr serve -p")
+}
+
+func toPtr[T any](val T) *T {
+	return &val
+}
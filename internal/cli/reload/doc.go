@@ -0,0 +1,4 @@
+// Package reload implements the "reload" command that validates the
+// configuration and asks the running RoadRunner server to reload it by
+// sending SIGHUP to the process identified by the pid file.
+package reload
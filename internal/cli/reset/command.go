@@ -2,7 +2,8 @@ package reset
 
 import (
 	"log"
-	"sync"
+	"strings"
+	"time"
 
 	internalRpc "github.com/roadrunner-server/roadrunner/v2025/internal/rpc"
 	"github.com/roadrunner-server/roadrunner/v2025/internal/sdnotify"
@@ -15,18 +16,26 @@ const (
 	op            = errors.Op("reset_handler")
 	resetterList  = "resetter.List"
 	resetterReset = "resetter.Reset"
+	// how long a single plugin might take to reset its workers
+	defaultTimeout = time.Minute
 )
 
 // NewCommand creates `reset` command.
 func NewCommand(cfgFile *string, override *[]string, silent *bool) *cobra.Command {
-	return &cobra.Command{
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
 		Use:   "reset",
 		Short: "Reset workers of all or specific RoadRunner service",
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			if cfgFile == nil {
 				return errors.E(op, errors.Str("no configuration file provided"))
 			}
 
+			if timeout <= 0 {
+				return errors.E(op, errors.Str("--timeout should be positive"))
+			}
+
 			client, err := internalRpc.NewClient(*cfgFile, *override)
 			if err != nil {
 				return err
@@ -43,37 +52,35 @@ func NewCommand(cfgFile *string, override *[]string, silent *bool) *cobra.Comman
 
 			_, _ = sdnotify.SdNotify(sdnotify.Reloading)
 
-			var wg sync.WaitGroup
-			wg.Add(len(plugins))
-
-			for _, plugin := range plugins {
-				// simulating some work
-				go func(p string) {
-					if !*silent {
-						log.Printf("resetting plugin: [%s] ", p)
-					}
-					defer wg.Done()
+			results := resetPlugins(client, plugins, timeout, *silent)
 
-					var done bool
-					<-client.Go(resetterReset, p, &done, nil).Done
-
-					if err != nil {
-						log.Println(err)
-
-						return
-					}
+			if !*silent {
+				_ = renderResults(cmd.OutOrStdout(), results).Render()
+			}
 
-					if !*silent {
-						log.Printf("plugin reset: [%s]", p)
-					}
-				}(plugin)
+			var failed []string
+			for _, r := range results {
+				if r.err != nil {
+					failed = append(failed, r.plugin)
+				}
 			}
 
-			wg.Wait()
+			// the service manager is told the reload is finished only when all the workers are back
+			if len(failed) > 0 {
+				return errors.E(op, errors.Errorf("failed to reset %d of %d plugin(s): %s", len(failed), len(results), strings.Join(failed, ", ")))
+			}
 
 			_, _ = sdnotify.SdNotify(sdnotify.Ready)
 
+			if !*silent {
+				log.Printf("%d plugin(s) reset", len(results))
+			}
+
 			return nil
 		},
 	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", defaultTimeout, "time to wait for every plugin to reset its workers")
+
+	return cmd
 }
@@ -1,3 +1,5 @@
 // Package reset implements the "reset" command that resets workers of all
-// or specific RoadRunner plugins concurrently via RPC.
+// or specific RoadRunner plugins concurrently via RPC, with a per-plugin
+// timeout, and prints the summary of the results. The command fails when any
+// of the plugins fails to reset.
 package reset
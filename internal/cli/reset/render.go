@@ -0,0 +1,47 @@
+package reset
+
+import (
+	"io"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+)
+
+// renderResults renders the summary of the reset: the plugin, how long it took and the outcome.
+func renderResults(writer io.Writer, results []*result) *tablewriter.Table {
+	tw := tablewriter.NewTable(writer, tablewriter.WithConfig(tableConfig()))
+	tw.Header([]string{"Plugin", "Duration", "Result"})
+
+	for _, r := range results {
+		_ = tw.Append([]string{r.plugin, r.duration.Round(time.Millisecond).String(), renderResult(r.err)})
+	}
+
+	return tw
+}
+
+func renderResult(err error) string {
+	if err != nil {
+		return color.RedString("FAILED: " + err.Error())
+	}
+
+	return color.GreenString("OK")
+}
+
+func tableConfig() tablewriter.Config {
+	return tablewriter.Config{
+		Header: tw.CellConfig{
+			Formatting: tw.CellFormatting{
+				AutoFormat: tw.On,
+				AutoWrap:   int(tw.Off),
+			},
+		},
+		MaxWidth: 150,
+		Row: tw.CellConfig{
+			Alignment: tw.CellAlignment{
+				Global: tw.AlignLeft,
+			},
+		},
+	}
+}
@@ -0,0 +1,77 @@
+package reset
+
+import (
+	"log"
+	"net/rpc"
+	"sync"
+	"time"
+
+	"github.com/roadrunner-server/errors"
+)
+
+// result is the outcome of the reset of a single plugin.
+type result struct {
+	plugin   string
+	duration time.Duration
+	err      error
+}
+
+// resetPlugins resets the plugins concurrently, the results are in the order of the plugins.
+func resetPlugins(client *rpc.Client, plugins []string, timeout time.Duration, silent bool) []*result {
+	results := make([]*result, len(plugins))
+
+	var wg sync.WaitGroup
+	wg.Add(len(plugins))
+
+	for i, plugin := range plugins {
+		go func() {
+			defer wg.Done()
+
+			if !silent {
+				log.Printf("resetting plugin: [%s] ", plugin)
+			}
+
+			start := time.Now()
+			err := resetPlugin(client, plugin, timeout)
+			results[i] = &result{plugin: plugin, duration: time.Since(start), err: err}
+
+			if !silent {
+				if err != nil {
+					log.Printf("plugin reset failed: [%s]: %v", plugin, err)
+					return
+				}
+
+				log.Printf("plugin reset: [%s]", plugin)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+func resetPlugin(client *rpc.Client, plugin string, timeout time.Duration) error {
+	var done bool
+
+	// the call is left running on timeout, the connection is closed once all the plugins are handled
+	call := client.Go(resetterReset, plugin, &done, make(chan *rpc.Call, 1))
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-call.Done:
+		if call.Error != nil {
+			return call.Error
+		}
+
+		if !done {
+			return errors.Str("the reset was not confirmed by the plugin")
+		}
+
+		return nil
+	case <-timer.C:
+		return errors.Errorf("timed out after %s", timeout)
+	}
+}
@@ -0,0 +1,120 @@
+package reset_test
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/roadrunner-server/roadrunner/v2025/internal/cli/reset"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/rpctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeResetter struct {
+	mu    sync.Mutex
+	reset []string
+	// plugin -> error message
+	fail map[string]string
+	// the plugin whose reset never finishes
+	hang    string
+	release chan struct{}
+}
+
+func (f *fakeResetter) List(_ bool, list *[]string) error {
+	*list = []string{"http", "grpc", "jobs"}
+	return nil
+}
+
+func (f *fakeResetter) Reset(plugin string, done *bool) error {
+	if plugin == f.hang {
+		<-f.release
+	}
+
+	if msg, ok := f.fail[plugin]; ok {
+		return errors.New(msg)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.reset = append(f.reset, plugin)
+	*done = true
+
+	return nil
+}
+
+func runReset(t *testing.T, cfg string, args ...string) (string, error) {
+	t.Helper()
+
+	silent := false
+	cmd := reset.NewCommand(&cfg, &[]string{}, &silent)
+
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+
+	return out.String(), err
+}
+
+func TestResetAll(t *testing.T) {
+	fr := &fakeResetter{}
+	cfg := rpctest.Config(t, map[string]any{"resetter": fr})
+
+	out, err := runReset(t, cfg)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"http", "grpc", "jobs"}, fr.reset)
+	assert.Contains(t, out, "PLUGIN")
+	assert.Contains(t, out, "DURATION")
+	assert.Contains(t, out, "RESULT")
+	assert.Contains(t, out, "OK")
+}
+
+func TestResetSelected(t *testing.T) {
+	fr := &fakeResetter{}
+	cfg := rpctest.Config(t, map[string]any{"resetter": fr})
+
+	_, err := runReset(t, cfg, "http")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"http"}, fr.reset)
+}
+
+func TestResetFailed(t *testing.T) {
+	fr := &fakeResetter{fail: map[string]string{"grpc": "no workers"}}
+	cfg := rpctest.Config(t, map[string]any{"resetter": fr})
+
+	out, err := runReset(t, cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to reset 1 of 3 plugin(s): grpc")
+
+	// the other plugins are reset anyway
+	assert.ElementsMatch(t, []string{"http", "jobs"}, fr.reset)
+	assert.Contains(t, out, "FAILED: no workers")
+}
+
+func TestResetTimeout(t *testing.T) {
+	fr := &fakeResetter{hang: "jobs", release: make(chan struct{})}
+	t.Cleanup(func() { close(fr.release) })
+	cfg := rpctest.Config(t, map[string]any{"resetter": fr})
+
+	start := time.Now()
+	out, err := runReset(t, cfg, "--timeout", "200ms")
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second*5)
+
+	assert.Contains(t, err.Error(), "failed to reset 1 of 3 plugin(s): jobs")
+	assert.Contains(t, out, "FAILED: timed out after 200ms")
+}
+
+func TestResetInvalidTimeout(t *testing.T) {
+	_, err := runReset(t, "", "--timeout", "0s")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--timeout should be positive")
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"cmp"
 	"context"
 	stderr "errors"
 	"fmt"
@@ -9,25 +10,29 @@ import (
 	"os/signal"
 	"path/filepath"
 	"runtime"
-	"strconv"
 	"syscall"
 
 	"github.com/joho/godotenv"
 	"github.com/roadrunner-server/errors"
+	"github.com/roadrunner-server/roadrunner/v2025/container"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/cli/config"
+	debugCmd "github.com/roadrunner-server/roadrunner/v2025/internal/cli/debug"
 	"github.com/roadrunner-server/roadrunner/v2025/internal/cli/jobs"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/cli/reload"
 	"github.com/roadrunner-server/roadrunner/v2025/internal/cli/reset"
 	"github.com/roadrunner-server/roadrunner/v2025/internal/cli/serve"
 	"github.com/roadrunner-server/roadrunner/v2025/internal/cli/stop"
 	"github.com/roadrunner-server/roadrunner/v2025/internal/cli/workers"
 	dbg "github.com/roadrunner-server/roadrunner/v2025/internal/debug"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/loader"
 	"github.com/roadrunner-server/roadrunner/v2025/internal/meta"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/pidfile"
 	"github.com/spf13/cobra"
 )
 
 const (
 	// env var name: path to the .env file
-	envDotenv   string = "DOTENV_PATH"
-	pidFileName string = ".pid"
+	envDotenv string = "DOTENV_PATH"
 )
 
 // NewCommand creates root command.
@@ -35,7 +40,7 @@ func NewCommand(cmdName string) *cobra.Command { //nolint:funlen,gocognit
 	// path to the .rr.yaml
 	cfgFile := toPtr("")
 	// pidfile path
-	pidFile := toPtr(false)
+	pidFile := toPtr("")
 	// force stop RR
 	forceStop := toPtr(false)
 	// override config values
@@ -49,8 +54,12 @@ func NewCommand(cmdName string) *cobra.Command { //nolint:funlen,gocognit
 	var workDir string
 	// path to the .env file
 	var dotenv string
+	// directory with the secrets for the ${secretdir:name} references
+	var secretsDir string
 	// debug mode
 	var debug bool
+	// debug server address, overrides endure.debug.address
+	var debugAddress string
 
 	cmd := &cobra.Command{
 		Use:           cmdName,
@@ -65,6 +74,18 @@ func NewCommand(cmdName string) *cobra.Command { //nolint:funlen,gocognit
 				return errors.Str("no configuration file provided")
 			}
 
+			// relative to the current directory, not to the working directory
+			if secretsDir != "" {
+				absDir, err := filepath.Abs(secretsDir)
+				if err != nil {
+					return err
+				}
+
+				if err = os.Setenv(loader.EnvSecretsDir, absDir); err != nil {
+					return err
+				}
+			}
+
 			// if user set the wd, change the current wd
 			if workDir != "" {
 				if err := os.Chdir(workDir); err != nil {
@@ -96,13 +117,25 @@ func NewCommand(cmdName string) *cobra.Command { //nolint:funlen,gocognit
 			}
 
 			if debug {
-				srv := dbg.NewServer()
+				containerCfg, err := container.NewConfig(*cfgFile, *override...)
+				if err != nil {
+					return err
+				}
+
+				debugCfg := &containerCfg.Debug
+				addr := cmp.Or(debugAddress, debugCfg.Address)
+
+				if !debugCfg.AuthEnabled() && len(debugCfg.AllowedIPs) == 0 && dbg.Exposed(addr) {
+					fmt.Printf("[WARN] debug server listens on %s without authentication, see endure.debug\n", addr)
+				}
+
+				srv := dbg.NewServer(debugCfg)
 				exit := make(chan os.Signal, 1)
 				stpErr := make(chan error, 1)
 				signal.Notify(exit, os.Interrupt, syscall.SIGTERM, syscall.SIGINT, syscall.SIGABRT)
 
 				go func() {
-					errS := srv.Start(":6061")
+					errS := srv.Start(addr)
 					// errS is always non-nil, this is just double check
 					if errS != nil && stderr.Is(errS, http.ErrServerClosed) {
 						return
@@ -126,22 +159,6 @@ func NewCommand(cmdName string) *cobra.Command { //nolint:funlen,gocognit
 				}()
 			}
 
-			// user wanted to write a .pid file
-			if *pidFile {
-				f, err := os.Create(pidFileName)
-				if err != nil {
-					return err
-				}
-				defer func() {
-					_ = f.Close()
-				}()
-
-				_, err = f.WriteString(strconv.Itoa(os.Getpid()))
-				if err != nil {
-					return err
-				}
-			}
-
 			return nil
 		},
 	}
@@ -150,20 +167,27 @@ func NewCommand(cmdName string) *cobra.Command { //nolint:funlen,gocognit
 
 	f.BoolVarP(experimental, "enable-experimental", "e", false, "enable experimental features")
 	f.BoolVarP(forceStop, "force", "f", false, "force stop")
-	f.BoolVarP(pidFile, "pid", "p", false, "create a .pid file")
+	f.StringVarP(pidFile, "pid", "p", "", "pid file path, written by serve and read by stop [endure.pid_file]")
+	// `-p` without the value keeps working, the path is passed as `--pid=path` or `-p=path`
+	f.Lookup("pid").NoOptDefVal = pidfile.DefaultPath
 	f.StringVarP(cfgFile, "config", "c", ".rr.yaml", "config file")
 	f.StringVarP(&workDir, "WorkDir", "w", "", "working directory")
 	f.StringVarP(&dotenv, "dotenv", "", "", fmt.Sprintf("dotenv file [$%s]", envDotenv))
+	f.StringVarP(&secretsDir, "secrets-dir", "", "", fmt.Sprintf("directory for the ${secretdir:name} references [$%s] (default /run/secrets)", loader.EnvSecretsDir))
 	f.BoolVarP(&debug, "debug", "d", false, "debug mode")
+	f.StringVar(&debugAddress, "debug-address", "", fmt.Sprintf("debug server address, e.g. unix:///run/rr-debug.sock [endure.debug.address] (default %s)", dbg.DefaultAddress))
 	f.BoolVarP(silent, "silent", "s", false, "do not print startup message")
 	f.StringArrayVarP(override, "override", "o", nil, "override config value (dot.notation=value)")
 
 	cmd.AddCommand(
 		workers.NewCommand(cfgFile, override),
 		reset.NewCommand(cfgFile, override, silent),
-		serve.NewCommand(override, cfgFile, silent, experimental),
-		stop.NewCommand(silent, forceStop),
+		serve.NewCommand(override, cfgFile, pidFile, silent, experimental),
+		stop.NewCommand(cfgFile, override, pidFile, silent, forceStop),
+		reload.NewCommand(cfgFile, override, pidFile, silent),
 		jobs.NewCommand(cfgFile, override, silent),
+		config.NewCommand(cfgFile, override, silent),
+		debugCmd.NewCommand(cfgFile, override, silent),
 	)
 
 	return cmd
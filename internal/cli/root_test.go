@@ -21,6 +21,7 @@ func TestCommandSubcommands(t *testing.T) {
 		{giveName: "workers"},
 		{giveName: "reset"},
 		{giveName: "serve"},
+		{giveName: "config"},
 	}
 
 	// get all existing subcommands and put into the map
@@ -51,6 +52,8 @@ func TestCommandFlags(t *testing.T) {
 		{giveName: "dotenv", wantShorthand: "", wantDefault: ""},
 		{giveName: "debug", wantShorthand: "d", wantDefault: "false"},
 		{giveName: "override", wantShorthand: "o", wantDefault: "[]"},
+		{giveName: "pid", wantShorthand: "p", wantDefault: ""},
+		{giveName: "debug-address", wantShorthand: "", wantDefault: ""},
 	}
 
 	for _, tt := range cases {
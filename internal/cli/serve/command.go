@@ -3,17 +3,22 @@
 package serve
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
+	"time"
 
-	"github.com/roadrunner-server/endure/v2"
 	"github.com/roadrunner-server/roadrunner/v2025/container"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/activation"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/handoff"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/loader"
 	"github.com/roadrunner-server/roadrunner/v2025/internal/meta"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/reload"
 	"github.com/roadrunner-server/roadrunner/v2025/internal/sdnotify"
 
-	configImpl "github.com/roadrunner-server/config/v6"
 	"github.com/roadrunner-server/errors"
 	"github.com/spf13/cobra"
 )
@@ -25,61 +30,84 @@ func log(msg string, silent bool) {
 	}
 }
 
-func NewCommand(override *[]string, cfgFile *string, silent *bool, experimental *bool) *cobra.Command { //nolint:funlen
+func NewCommand(override *[]string, cfgFile *string, pidFile *string, silent *bool, experimental *bool) *cobra.Command { //nolint:funlen
 	return &cobra.Command{
 		Use:   "serve",
 		Short: "Start RoadRunner server",
-		RunE: func(*cobra.Command, []string) error {
+		RunE: func(*cobra.Command, []string) (err error) {
 			const op = errors.Op("handle_serve_command")
+
+			// started by the graceful restart: the previous process keeps serving until this one is ready, the startup
+			// error is reported to it
+			handoff.Setup()
+			defer func() {
+				if err != nil {
+					handoff.Fail(err)
+				}
+			}()
+
+			// the sockets passed by systemd, referenced as systemd:NAME
+			if err = activation.Setup(); err != nil {
+				return errors.E(op, err)
+			}
+
 			// just to be safe
 			if cfgFile == nil {
 				return errors.E(op, errors.Str("no configuration file provided"))
 			}
 
-			// create endure container config
-			containerCfg, err := container.NewConfig(*cfgFile)
+			// resolve the configuration once: the container and all the plugins see the same values
+			resolved, err := loader.Load(*cfgFile, *override)
 			if err != nil {
 				return errors.E(op, err)
 			}
 
-			cfg := &configImpl.Plugin{
-				Path:                 *cfgFile,
-				Timeout:              containerCfg.GracePeriod,
-				Flags:                *override,
-				Version:              meta.Version(),
-				ExperimentalFeatures: *experimental,
-			}
-
-			endureOptions := []endure.Options{
-				endure.GracefulShutdownTimeout(containerCfg.GracePeriod),
-			}
+			// errors reported by the plugins might contain the values read from the secret files
+			defer func() {
+				err = resolved.RedactError(err)
+			}()
 
-			if containerCfg.PrintGraph {
-				endureOptions = append(endureOptions, endure.Visualize())
+			if err = activation.Check(resolved.Settings()); err != nil {
+				return errors.E(op, err)
 			}
 
-			// create endure container
-			ll, err := container.ParseLogLevel(containerCfg.LogLevel)
+			// create endure container config
+			containerCfg, err := container.ParseConfig(resolved.Viper())
 			if err != nil {
-				log(fmt.Sprintf("[WARN] Failed to parse log level, using default (error): %v", err), *silent)
+				return errors.E(op, err)
 			}
 
-			cont := endure.New(ll, endureOptions...)
+			// the flag takes precedence over the configuration
+			path := containerCfg.PidFile
+			if pidFile != nil && *pidFile != "" {
+				path = *pidFile
+			}
 
-			// register plugins
-			err = cont.RegisterAll(append(container.Plugins(), cfg)...)
+			pf, err := openPidFile(path)
 			if err != nil {
 				return errors.E(op, err)
 			}
 
-			// init container and all services
-			err = cont.Init()
+			// the file is left on the forced exit, the lock is released by the OS anyway
+			defer func() {
+				if pf != nil {
+					_ = pf.Remove()
+				}
+			}()
+
+			profiler, err := startProfiler(containerCfg, *silent)
 			if err != nil {
 				return errors.E(op, err)
 			}
 
-			// start serving the graph
-			errCh, err := cont.Serve()
+			defer func() {
+				profiler.Stop()
+			}()
+
+			// the plugins might take a while to start, e.g. allocating the workers
+			notify(sdnotify.Status("starting"), sdnotify.ExtendTimeout(containerCfg.RestartTimeout))
+
+			inst, err := startContainer(resolved, containerCfg, *experimental, *silent)
 			if err != nil {
 				return errors.E(op, err)
 			}
@@ -90,15 +118,23 @@ func NewCommand(override *[]string, cfgFile *string, silent *bool, experimental
 			restartCh := make(chan os.Signal, 1)
 			signal.Notify(restartCh, syscall.SIGUSR2)
 
+			reloadCh := make(chan os.Signal, 1)
+			signal.Notify(reloadCh, syscall.SIGHUP)
+
+			// the result of the graceful restart, the new process is started in the background
+			handoffCh := make(chan *handoffResult, 1)
+			// cancels the pending restart, the new process should not outlive the stopped server
+			restartCtx, cancelRestart := context.WithCancel(context.Background())
+			defer cancelRestart()
+
+			var pending bool
+
 			go func() {
 				// first catch - stop the container
 				<-oss
 				// send signal to stop execution
 				stop <- struct{}{}
 
-				// notify about stopping
-				_, _ = sdnotify.SdNotify(sdnotify.Stopping)
-
 				// after the first hit we are waiting for the second catch - exit from the process
 				<-oss
 				log("exit forced", *silent)
@@ -108,17 +144,34 @@ func NewCommand(override *[]string, cfgFile *string, silent *bool, experimental
 			log(fmt.Sprintf("[INFO] RoadRunner server started; version: %s, buildtime: %s", meta.Version(), meta.BuildTime()), *silent)
 
 			// at this moment, we're almost sure that the container is running (almost- because we don't know if the plugins won't report an error on the next step)
-			notified, err := sdnotify.SdNotify(sdnotify.Ready)
+			notified, err := sdnotify.SdNotify(sdnotify.Join(sdnotify.Ready, sdnotify.Status(statusServing)))
 			if err != nil {
 				log(fmt.Sprintf("[WARN] sdnotify: %s", err), *silent)
 			}
 
+			// the watchdog checks the critical plugins, the checker is replaced on reload
+			var (
+				health   atomic.Pointer[healthChecker]
+				interval time.Duration
+			)
+
 			if notified {
 				log("[INFO] sdnotify: notified", *silent)
+				notifyStatus(resolved.Viper())
+
 				stopCh := make(chan struct{}, 1)
-				if containerCfg.WatchdogSec > 0 {
-					log(fmt.Sprintf("[INFO] sdnotify: watchdog enabled, timeout: %d seconds", containerCfg.WatchdogSec), *silent)
-					sdnotify.StartWatchdog(containerCfg.WatchdogSec, stopCh)
+				var errW error
+				interval, errW = watchdogInterval(containerCfg)
+				if errW != nil {
+					log(fmt.Sprintf("[WARN] sdnotify: %s", errW), *silent)
+				}
+
+				if interval > 0 {
+					log(fmt.Sprintf("[INFO] sdnotify: watchdog enabled, interval: %s", interval), *silent)
+					health.Store(newHealthChecker(resolved.Viper(), containerCfg, interval/2, *silent))
+					sdnotify.StartWatchdog(interval, stopCh, func() bool {
+						return health.Load().healthy()
+					})
 				}
 
 				// if notified -> notify about stop
@@ -127,21 +180,171 @@ func NewCommand(override *[]string, cfgFile *string, silent *bool, experimental
 				}()
 			}
 
+			// the previous process stops serving once this one is ready
+			if err = handoff.Ready(); err != nil {
+				log(fmt.Sprintf("[WARN] failed to notify the previous process: %s", err), *silent)
+			} else if pf != nil {
+				// the adopted pid file keeps the PID of the previous process until now
+				if errO := pf.Own(); errO != nil {
+					log(fmt.Sprintf("[WARN] failed to write the pid file: %s", errO), *silent)
+				}
+			}
+
+			// useConfig switches serve to the reloaded configuration
+			useConfig := func(next *loader.Config, nextCfg *container.Config) {
+				resolved, containerCfg = next, nextCfg
+				if interval > 0 {
+					health.Store(newHealthChecker(resolved.Viper(), containerCfg, interval/2, *silent))
+				}
+
+				// the profiler options might be changed as well
+				profiler.Stop()
+				var errP error
+				if profiler, errP = startProfiler(containerCfg, *silent); errP != nil {
+					log(fmt.Sprintf("[ERROR] profiler: %s", errP), *silent)
+				}
+
+				notify(sdnotify.Ready, sdnotify.Status(statusServing))
+				notifyStatus(resolved.Viper())
+			}
+
 			for {
 				select {
-				case e := <-errCh:
+				case e := <-inst.errCh:
 					return fmt.Errorf("error: %w\nplugin: %s", e.Error, e.VertexID)
 				case <-stop: // stop the container after the first signal
 					log(fmt.Sprintf("stop signal received, grace timeout is: %0.f seconds", containerCfg.GracePeriod.Seconds()), *silent)
 
-					if err = cont.Stop(); err != nil {
+					// the plugins have the grace period to stop
+					notify(sdnotify.Stopping, sdnotify.Status("stopping"), sdnotify.ExtendTimeout(containerCfg.GracePeriod))
+
+					if pending {
+						cancelRestart()
+						<-handoffCh
+					}
+
+					if err = inst.cont.Stop(); err != nil {
 						return fmt.Errorf("error: %w", err)
 					}
 
 					return nil
 
+				case res := <-handoffCh:
+					pending = false
+
+					if res.err != nil {
+						log(fmt.Sprintf("[ERROR] restart failed, the server keeps running with the current process: %s", res.err), *silent)
+						// the reload might be in progress
+						notify(sdnotify.Ready, sdnotify.Status(statusServing))
+						notifyStatus(resolved.Viper())
+
+						continue
+					}
+
+					log(fmt.Sprintf("[INFO] new process %d is ready, stopping the current one; grace timeout is: %0.f seconds", res.pid, containerCfg.GracePeriod.Seconds()), *silent)
+
+					// the service manager follows the new process
+					notify(sdnotify.MainPID(res.pid))
+
+					// the pid file is owned by the new process
+					if pf != nil {
+						_ = pf.Close()
+						pf = nil
+					}
+
+					if err = inst.cont.Stop(); err != nil {
+						return fmt.Errorf("error: %w", err)
+					}
+
+					return nil
+
+				case <-reloadCh:
+					log("reload signal [SIGHUP] received", *silent)
+					// the plugins might be stopped and started again
+					notify(sdnotify.ReloadingNow(), sdnotify.Status("reloading configuration"),
+						sdnotify.ExtendTimeout(containerCfg.GracePeriod+containerCfg.RestartTimeout))
+
+					// the invalid configuration is rejected, the server keeps running with the current one
+					next, errL := reload.Load(*cfgFile, *override)
+					if errL == nil {
+						errL = activation.Check(next.Settings())
+					}
+
+					if errL != nil {
+						log(fmt.Sprintf("[ERROR] reload rejected, the current configuration is kept: %s", errL), *silent)
+						notify(sdnotify.Ready, sdnotify.Status("serving, reload rejected: invalid configuration"))
+
+						continue
+					}
+
+					changes := reload.Diff(resolved.Settings(), next.Settings())
+					if len(changes) == 0 {
+						log("[INFO] configuration is not changed, nothing to reload", *silent)
+						notify(sdnotify.Ready, sdnotify.Status(statusServing))
+						notifyStatus(resolved.Viper())
+
+						continue
+					}
+
+					log(fmt.Sprintf("[INFO] configuration changed: %s", joinChanges(changes)), *silent)
+
+					// the pool size and the options used by serve are applied without restarting the plugins
+					plan := reload.NewPlan(resolved.Settings(), next.Settings(), changes)
+					if len(plan.Restart) == 0 {
+						nextCfg, errA := applyPlan(plan, inst, next, containerCfg.RestartTimeout)
+						if errA == nil {
+							useConfig(next, nextCfg)
+							log("[INFO] configuration applied without restarting the plugins", *silent)
+
+							continue
+						}
+
+						log(fmt.Sprintf("[WARN] failed to apply the configuration to the running plugins, restarting them: %s", errA), *silent)
+					} else {
+						log(fmt.Sprintf("[INFO] the changes can't be applied to the running plugins: %s", joinChanges(plan.Restart)), *silent)
+					}
+
+					// the new process reads the new configuration, the current one keeps serving until it's ready
+					if containerCfg.RestartMode == container.RestartGraceful || containerCfg.RestartMode == container.RestartUpgrade {
+						if pending {
+							log("[WARN] restart is already in progress", *silent)
+							continue
+						}
+
+						pending = true
+						go gracefulRestart(restartCtx, containerCfg, *silent, handoffCh)
+
+						continue
+					}
+
+					// the running container is kept when the next one can't be initialized
+					reloaded, errR := reloadContainer(inst, next, *experimental, *silent)
+					if reloaded == nil {
+						return errR
+					}
+
+					if errR != nil {
+						log(fmt.Sprintf("[ERROR] reload failed, the server keeps running with the current configuration: %s", errR), *silent)
+					}
+
+					inst = reloaded
+					useConfig(inst.config.Config, inst.cfg)
+
 				case <-restartCh:
 					log("restart signal [SIGUSR2] received", *silent)
+
+					if containerCfg.RestartMode == container.RestartGraceful || containerCfg.RestartMode == container.RestartUpgrade {
+						if pending {
+							log("[WARN] restart is already in progress", *silent)
+							continue
+						}
+
+						pending = true
+						go gracefulRestart(restartCtx, containerCfg, *silent, handoffCh)
+
+						continue
+					}
+
 					executable, err := os.Executable()
 					if err != nil {
 						log(fmt.Sprintf("restart failed: %s", err), *silent)
@@ -150,7 +353,7 @@ func NewCommand(override *[]string, cfgFile *string, silent *bool, experimental
 					args := os.Args
 					env := os.Environ()
 
-					if err = cont.Stop(); err != nil {
+					if err = inst.cont.Stop(); err != nil {
 						log(fmt.Sprintf("restart failed: %s", err), *silent)
 						return errors.E("failed to restart")
 					}
@@ -7,13 +7,15 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/roadrunner-server/endure/v2"
 	"github.com/roadrunner-server/roadrunner/v2025/container"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/configurer"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/loader"
 	"github.com/roadrunner-server/roadrunner/v2025/internal/meta"
 	"github.com/roadrunner-server/roadrunner/v2025/internal/sdnotify"
 
-	configImpl "github.com/roadrunner-server/config/v6"
 	"github.com/roadrunner-server/errors"
 	"github.com/spf13/cobra"
 )
@@ -25,27 +27,64 @@ func log(msg string, silent bool) {
 	}
 }
 
-func NewCommand(override *[]string, cfgFile *string, silent *bool, experimental *bool) *cobra.Command { //nolint:funlen
+func NewCommand(override *[]string, cfgFile *string, pidFile *string, silent *bool, experimental *bool) *cobra.Command { //nolint:funlen
 	return &cobra.Command{
 		Use:   "serve",
 		Short: "Start RoadRunner server",
-		RunE: func(*cobra.Command, []string) error {
+		RunE: func(*cobra.Command, []string) (err error) {
 			const op = errors.Op("handle_serve_command")
 			// just to be safe
 			if cfgFile == nil {
 				return errors.E(op, errors.Str("no configuration file provided"))
 			}
 
+			// resolve the configuration once: the container and all the plugins see the same values
+			resolved, err := loader.Load(*cfgFile, *override)
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			// errors reported by the plugins might contain the values read from the secret files
+			defer func() {
+				err = resolved.RedactError(err)
+			}()
+
 			// create endure container config
-			containerCfg, err := container.NewConfig(*cfgFile)
+			containerCfg, err := container.ParseConfig(resolved.Viper())
 			if err != nil {
 				return errors.E(op, err)
 			}
 
-			cfg := &configImpl.Plugin{
-				Path:                 *cfgFile,
+			// the flag takes precedence over the configuration
+			path := containerCfg.PidFile
+			if pidFile != nil && *pidFile != "" {
+				path = *pidFile
+			}
+
+			pf, err := openPidFile(path)
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			// the file is left on the forced exit, the lock is released by the OS anyway
+			defer func() {
+				if pf != nil {
+					_ = pf.Remove()
+				}
+			}()
+
+			profiler, err := startProfiler(containerCfg, *silent)
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			defer func() {
+				profiler.Stop()
+			}()
+
+			cfg := &configurer.Plugin{
+				Config:               resolved,
 				Timeout:              containerCfg.GracePeriod,
-				Flags:                *override,
 				Version:              meta.Version(),
 				ExperimentalFeatures: *experimental,
 			}
@@ -115,7 +154,7 @@ func NewCommand(override *[]string, cfgFile *string, silent *bool, experimental
 				stopCh := make(chan struct{}, 1)
 				if containerCfg.WatchdogSec > 0 {
 					log(fmt.Sprintf("[INFO] sdnotify: watchdog enabled, timeout: %d seconds", containerCfg.WatchdogSec), *silent)
-					sdnotify.StartWatchdog(containerCfg.WatchdogSec, stopCh)
+					sdnotify.StartWatchdog(time.Duration(containerCfg.WatchdogSec)*time.Second, stopCh, nil)
 				}
 
 				// if notified -> notify about stop
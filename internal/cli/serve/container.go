@@ -0,0 +1,292 @@
+//go:build !windows
+
+package serve
+
+import (
+	"cmp"
+	stderr "errors"
+	"fmt"
+	"maps"
+	"net/rpc"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/roadrunner-server/endure/v2"
+	"github.com/roadrunner-server/errors"
+	goridgeRpc "github.com/roadrunner-server/goridge/v4/pkg/rpc"
+	"github.com/roadrunner-server/informer/v6"
+	"github.com/roadrunner-server/roadrunner/v2025/container"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/configurer"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/loader"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/meta"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/reload"
+	internalRpc "github.com/roadrunner-server/roadrunner/v2025/internal/rpc"
+)
+
+// instance is the container with the configuration it was created with.
+type instance struct {
+	cont  *endure.Endure
+	errCh <-chan *endure.Result
+	// serves the configuration to the plugins
+	config *configurer.Plugin
+	cfg    *container.Config
+}
+
+// startContainer registers the plugins with the resolved configuration and starts serving.
+func startContainer(resolved *loader.Config, containerCfg *container.Config, experimental, silent bool) (*instance, error) {
+	inst, err := newContainer(resolved, containerCfg, experimental, silent)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = inst.serve(); err != nil {
+		return nil, err
+	}
+
+	return inst, nil
+}
+
+// newContainer registers the plugins with the resolved configuration and initializes them, the plugins are not served.
+func newContainer(resolved *loader.Config, containerCfg *container.Config, experimental, silent bool) (*instance, error) {
+	cfg := &configurer.Plugin{
+		Config:               resolved,
+		Timeout:              containerCfg.GracePeriod,
+		Version:              meta.Version(),
+		ExperimentalFeatures: experimental,
+	}
+
+	endureOptions := []endure.Options{
+		endure.GracefulShutdownTimeout(containerCfg.GracePeriod),
+	}
+
+	if containerCfg.PrintGraph {
+		endureOptions = append(endureOptions, endure.Visualize())
+	}
+
+	// create endure container
+	ll, err := container.ParseLogLevel(containerCfg.LogLevel)
+	if err != nil {
+		log(fmt.Sprintf("[WARN] Failed to parse log level, using default (error): %v", err), silent)
+	}
+
+	cont := endure.New(ll, endureOptions...)
+
+	// register plugins
+	err = cont.RegisterAll(append(container.Plugins(), cfg)...)
+	if err != nil {
+		return nil, err
+	}
+
+	// init container and all services
+	err = cont.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return &instance{cont: cont, config: cfg, cfg: containerCfg}, nil
+}
+
+// serve starts serving the graph.
+func (i *instance) serve() error {
+	errCh, err := i.cont.Serve()
+	if err != nil {
+		return err
+	}
+
+	i.errCh = errCh
+
+	return nil
+}
+
+// reloadContainer stops the running container and starts a new one with the next configuration. It's used for the
+// changes which can't be applied to the running plugins (see reload.NewPlan): endure has no per-plugin restart, so the
+// whole container is restarted and the plugins listeners are closed until it's served again.
+//
+// The plugins are initialized with the next configuration and with the current one (the fallback) before the running
+// container is stopped: when either fails, the reload is rejected and the running container is returned with the
+// error. When the next container fails to serve, the fallback is served and returned with the error. Only when the
+// fallback fails as well, no container is returned.
+func reloadContainer(inst *instance, next *loader.Config, experimental, silent bool) (*instance, error) {
+	const op = errors.Op("reload_container")
+
+	// already validated by reload.Load
+	nextCfg, err := container.ParseConfig(next.Viper())
+	if err != nil {
+		return inst, errors.E(op, err)
+	}
+
+	nc, err := newContainer(next, nextCfg, experimental, silent)
+	if err != nil {
+		return inst, errors.E(op, next.RedactError(err))
+	}
+
+	fallback, err := newContainer(inst.config.Config, inst.cfg, experimental, silent)
+	if err != nil {
+		return inst, errors.E(op, inst.config.Config.RedactError(err))
+	}
+
+	log(fmt.Sprintf("[INFO] stopping the plugins; grace timeout is: %0.f seconds", inst.cfg.GracePeriod.Seconds()), silent)
+
+	if err = inst.cont.Stop(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	if err = nc.serve(); err == nil {
+		log("[INFO] configuration reloaded", silent)
+		return nc, nil
+	}
+
+	// endure doesn't stop the plugins served before the failed one
+	_ = nc.cont.Stop()
+
+	err = next.RedactError(err)
+	log(fmt.Sprintf("[ERROR] failed to start with the new configuration, restoring the previous one: %s", err), silent)
+
+	if errR := fallback.serve(); errR != nil {
+		_ = fallback.cont.Stop()
+		return nil, errors.E(op, stderr.Join(err, errR))
+	}
+
+	return fallback, errors.E(op, err)
+}
+
+func joinChanges(changes []reload.Change) string {
+	s := make([]string, 0, len(changes))
+	for _, c := range changes {
+		s = append(s, c.String())
+	}
+
+	return strings.Join(s, ", ")
+}
+
+// applyPlan applies the changes planned by reload.NewPlan to the running plugins and returns the endure options of the
+// next configuration, which are used by serve (restart mode, profiler, watchdog plugins).
+func applyPlan(plan *reload.Plan, inst *instance, next *loader.Config, timeout time.Duration) (*container.Config, error) {
+	// already validated by reload.Load
+	nextCfg, err := container.ParseConfig(next.Viper())
+	if err != nil {
+		return nil, err
+	}
+
+	current := inst.config.Config
+
+	// the rpc section is not changed, otherwise the plugins are restarted
+	var rpcAddr string
+	if v := current.Viper(); v.IsSet("rpc") {
+		rpcAddr = v.GetString("rpc.listen")
+	}
+
+	// the plugins read the new pool options on reset, the reset by `rr reset` keeps the new number of workers
+	if err = inst.config.Update(next); err != nil {
+		return nil, err
+	}
+
+	err = resetWorkers(rpcAddr, plan.Reset, timeout)
+	if err == nil {
+		err = scaleWorkers(rpcAddr, plan.Scale, timeout)
+	}
+
+	if err != nil {
+		// the plugins are restarted or keep running with the current configuration
+		_ = inst.config.Update(current)
+		return nil, err
+	}
+
+	return nextCfg, nil
+}
+
+// resetWorkers resets the workers of the plugins via the resetter RPC, the new workers are started with the pool
+// options the plugins read on reset.
+func resetWorkers(rpcAddr string, plugins []string, timeout time.Duration) error {
+	if len(plugins) == 0 {
+		return nil
+	}
+
+	client, err := dialRPC(rpcAddr, timeout)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = client.Close() }()
+
+	for _, plugin := range plugins {
+		var done bool
+		if err = client.Call(resetterReset, plugin, &done); err != nil {
+			return errors.Errorf("failed to reset the %s workers: %v", plugin, err)
+		}
+	}
+
+	return nil
+}
+
+// scaleWorkers adds or removes the workers of the plugins via the informer RPC until the pools have the planned size.
+// The error is returned when the size isn't reached, e.g. the pool in the debug mode has no persistent workers.
+func scaleWorkers(rpcAddr string, scale map[string]int, timeout time.Duration) error {
+	if len(scale) == 0 {
+		return nil
+	}
+
+	client, err := dialRPC(rpcAddr, timeout)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = client.Close() }()
+
+	for _, plugin := range slices.Sorted(maps.Keys(scale)) {
+		target := scale[plugin]
+
+		n, err := numWorkers(client, plugin)
+		if err != nil {
+			return err
+		}
+
+		method := informerAddWorker
+		if n > target {
+			method = informerRemoveWorker
+		}
+
+		for ; n != target; n += cmp.Compare(target, n) {
+			var done bool
+			if err = client.Call(method, plugin, &done); err != nil {
+				return errors.Errorf("failed to scale the %s workers: %v", plugin, err)
+			}
+		}
+
+		if n, err = numWorkers(client, plugin); err != nil {
+			return err
+		}
+
+		if n != target {
+			return errors.Errorf("the %s pool has %d workers after scaling, expected %d", plugin, n, target)
+		}
+	}
+
+	return nil
+}
+
+func numWorkers(client *rpc.Client, plugin string) (int, error) {
+	list := &informer.WorkerList{}
+	if err := client.Call(informerWorkers, plugin, &list); err != nil {
+		return 0, errors.Errorf("failed to get the workers of %s: %v", plugin, err)
+	}
+
+	return len(list.Workers), nil
+}
+
+// dialRPC connects to the rpc plugin, the connection is closed after the timeout.
+func dialRPC(rpcAddr string, timeout time.Duration) (*rpc.Client, error) {
+	if rpcAddr == "" {
+		return nil, errors.Str("the pool changes are applied via RPC, but the rpc plugin is not configured")
+	}
+
+	conn, err := internalRpc.Dialer(rpcAddr)
+	if err != nil {
+		return nil, errors.Errorf("failed to connect to RPC: %v", err)
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	return rpc.NewClientWithCodec(goridgeRpc.NewClientCodec(conn)), nil
+}
@@ -0,0 +1,130 @@
+//go:build !windows
+
+package serve
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/roadrunner-server/roadrunner/v2025/internal/configurer"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/loader"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/reload"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/rpctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaleWorkers(t *testing.T) {
+	fi := &fakeInformer{workers: map[string][]string{
+		"http": {"ready", "working"},
+		"jobs": {"ready", "ready", "ready"},
+	}}
+	addr := rpctest.Serve(t, map[string]any{"informer": fi})
+
+	require.NoError(t, scaleWorkers(addr, map[string]int{"http": 4, "jobs": 1}, time.Second*5))
+	assert.Len(t, fi.workers["http"], 4)
+	assert.Len(t, fi.workers["jobs"], 1)
+
+	// nothing to scale, RPC is not used
+	require.NoError(t, scaleWorkers("", nil, time.Second))
+}
+
+func TestScaleWorkersFailed(t *testing.T) {
+	fi := &fakeInformer{workers: map[string][]string{"http": {"ready"}}, fixed: []string{"http"}}
+	addr := rpctest.Serve(t, map[string]any{"informer": fi})
+
+	err := scaleWorkers(addr, map[string]int{"http": 2}, time.Second*5)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the http pool has 1 workers after scaling, expected 2")
+
+	err = scaleWorkers("", map[string]int{"http": 2}, time.Second)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the rpc plugin is not configured")
+}
+
+type fakeResetter struct {
+	mu    sync.Mutex
+	reset []string
+}
+
+func (f *fakeResetter) Reset(plugin string, done *bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.reset = append(f.reset, plugin)
+	*done = true
+
+	return nil
+}
+
+func TestResetWorkers(t *testing.T) {
+	fr := &fakeResetter{}
+	addr := rpctest.Serve(t, map[string]any{"resetter": fr})
+
+	require.NoError(t, resetWorkers(addr, []string{"grpc", "http"}, time.Second*5))
+	assert.Equal(t, []string{"grpc", "http"}, fr.reset)
+
+	// nothing to reset, RPC is not used
+	require.NoError(t, resetWorkers("", nil, time.Second))
+
+	err := resetWorkers("", []string{"http"}, time.Second)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the rpc plugin is not configured")
+}
+
+func loadConfig(t *testing.T, rpcAddr, pool string) *loader.Config {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), ".rr.yaml")
+	require.NoError(t, os.WriteFile(path, fmt.Appendf(nil, "version: \"3\"\nrpc:\n  listen: %s\nhttp:\n  pool: %s\n", rpcAddr, pool), 0o600))
+
+	c, err := loader.Load(path, nil)
+	require.NoError(t, err)
+
+	return c
+}
+
+// the running plugins read the new pool options on reset, the scaled size is kept in their configuration
+func TestApplyPlan(t *testing.T) {
+	fi := &fakeInformer{workers: map[string][]string{"http": {"ready", "ready"}}}
+	fr := &fakeResetter{}
+	addr := rpctest.Serve(t, map[string]any{"informer": fi, "resetter": fr})
+
+	current := loadConfig(t, addr, "{num_workers: 2, max_jobs: 10}")
+	next := loadConfig(t, addr, "{num_workers: 4, max_jobs: 100}")
+
+	p := &configurer.Plugin{Config: current}
+	require.NoError(t, p.Init())
+
+	plan := reload.NewPlan(current.Settings(), next.Settings(), reload.Diff(current.Settings(), next.Settings()))
+	_, err := applyPlan(plan, &instance{config: p}, next, time.Second*5)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"http"}, fr.reset)
+	assert.Len(t, fi.workers["http"], 4)
+	assert.Equal(t, 4, p.Get("http.pool.num_workers"))
+	assert.Equal(t, 100, p.Get("http.pool.max_jobs"))
+}
+
+// the plugins keep the current configuration when the changes are not applied
+func TestApplyPlanFailed(t *testing.T) {
+	fi := &fakeInformer{workers: map[string][]string{"http": {"ready"}}, fixed: []string{"http"}}
+	addr := rpctest.Serve(t, map[string]any{"informer": fi})
+
+	current := loadConfig(t, addr, "{num_workers: 1}")
+	next := loadConfig(t, addr, "{num_workers: 2}")
+
+	p := &configurer.Plugin{Config: current}
+	require.NoError(t, p.Init())
+
+	plan := reload.NewPlan(current.Settings(), next.Settings(), reload.Diff(current.Settings(), next.Settings()))
+	_, err := applyPlan(plan, &instance{config: p}, next, time.Second*5)
+	require.Error(t, err)
+
+	assert.Same(t, current, p.Config)
+	assert.Equal(t, 1, p.Get("http.pool.num_workers"))
+}
@@ -1,4 +1,5 @@
 // Package serve implements the "serve" command that starts the RoadRunner
 // server, manages the Endure container lifecycle, handles OS signals for
-// graceful shutdown and restart, and integrates with systemd via sdnotify.
+// graceful shutdown, restart and configuration reload (SIGHUP), and
+// integrates with systemd via sdnotify.
 package serve
@@ -0,0 +1,188 @@
+package serve
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/rpc"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/roadrunner-server/errors"
+	goridgeRpc "github.com/roadrunner-server/goridge/v4/pkg/rpc"
+	"github.com/roadrunner-server/informer/v6"
+	"github.com/roadrunner-server/roadrunner/v2025/container"
+	internalRpc "github.com/roadrunner-server/roadrunner/v2025/internal/rpc"
+	"github.com/spf13/viper"
+)
+
+// the worker states meaning the pool is able to handle the requests
+var liveWorkerStates = []string{"ready", "working"}
+
+// healthChecker checks the critical plugins (endure.watchdog_critical_plugins) before every watchdog ping: the health
+// check of the status plugin (/health?plugin=) and the worker pool states from the informer. The readiness check is
+// not used, the pool with all the workers busy is not ready, but it is still alive.
+type healthChecker struct {
+	plugins []string
+	// the status plugin address, empty when the plugin is not configured
+	statusAddr string
+	// the RPC address, empty when the plugin is not configured
+	rpcAddr string
+	// how long a single check might take, the watchdog interval should not be exceeded
+	timeout time.Duration
+	client  *http.Client
+	silent  bool
+	// the watchdog is paused, used to log the transitions only
+	paused bool
+}
+
+// newHealthChecker returns nil when no critical plugins are configured.
+func newHealthChecker(v *viper.Viper, cfg *container.Config, timeout time.Duration, silent bool) *healthChecker {
+	if len(cfg.WatchdogCritical) == 0 {
+		return nil
+	}
+
+	h := &healthChecker{
+		plugins: cfg.WatchdogCritical,
+		timeout: timeout,
+		client:  &http.Client{},
+		silent:  silent,
+	}
+
+	if v.IsSet("status") {
+		h.statusAddr = v.GetString("status.address")
+	}
+
+	if v.IsSet("rpc") {
+		h.rpcAddr = v.GetString("rpc.listen")
+	}
+
+	return h
+}
+
+// healthy is consulted by the watchdog, nil checker is always healthy.
+func (h *healthChecker) healthy() bool {
+	if h == nil {
+		return true
+	}
+
+	err := h.check()
+	switch {
+	case err != nil && !h.paused:
+		log(fmt.Sprintf("[ERROR] sdnotify: watchdog paused, the service will be restarted unless it recovers: %s", err), h.silent)
+	case err == nil && h.paused:
+		log("[INFO] sdnotify: watchdog resumed, the critical plugins are healthy", h.silent)
+	}
+
+	h.paused = err != nil
+
+	return err == nil
+}
+
+// check returns the error describing the first unhealthy plugin.
+func (h *healthChecker) check() error {
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	pools, err := h.workers(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, plugin := range h.plugins {
+		if err = h.checkStatus(ctx, plugin); err != nil {
+			return errors.Errorf("plugin %s is unhealthy: %v", plugin, err)
+		}
+
+		if list, ok := pools[plugin]; ok {
+			if err = checkWorkers(list); err != nil {
+				return errors.Errorf("plugin %s is unhealthy: %v", plugin, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (h *healthChecker) checkStatus(ctx context.Context, plugin string) error {
+	if h.statusAddr == "" {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+h.statusAddr+"/health?plugin="+plugin, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return errors.Errorf("health check failed: %v", err)
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("health check responded with %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// workers returns the workers of the critical plugins which have the worker pools.
+func (h *healthChecker) workers(ctx context.Context) (map[string]*informer.WorkerList, error) {
+	if h.rpcAddr == "" {
+		return nil, nil
+	}
+
+	conn, err := internalRpc.Dialer(h.rpcAddr)
+	if err != nil {
+		return nil, errors.Errorf("failed to connect to RPC: %v", err)
+	}
+
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+
+	client := rpc.NewClientWithCodec(goridgeRpc.NewClientCodec(conn))
+	defer func() { _ = client.Close() }()
+
+	var plugins []string
+	if err = client.Call(informerList, true, &plugins); err != nil {
+		return nil, errors.Errorf("failed to get the list of plugins: %v", err)
+	}
+
+	pools := make(map[string]*informer.WorkerList, len(h.plugins))
+	for _, plugin := range h.plugins {
+		// the plugin without the worker pool
+		if !slices.Contains(plugins, plugin) {
+			continue
+		}
+
+		list := &informer.WorkerList{}
+		if err = client.Call(informerWorkers, plugin, &list); err != nil {
+			return nil, errors.Errorf("failed to get the workers of %s: %v", plugin, err)
+		}
+
+		pools[plugin] = list
+	}
+
+	return pools, nil
+}
+
+// checkWorkers returns an error when no worker is ready or working, e.g. all of them are stuck after the errors.
+func checkWorkers(list *informer.WorkerList) error {
+	states := make([]string, 0, len(list.Workers))
+	for _, w := range list.Workers {
+		if slices.Contains(liveWorkerStates, w.StatusStr) {
+			return nil
+		}
+
+		states = append(states, w.StatusStr)
+	}
+
+	if len(states) == 0 {
+		return errors.Str("no workers")
+	}
+
+	return errors.Errorf("no workers ready or working (%s)", strings.Join(states, ", "))
+}
@@ -0,0 +1,165 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/roadrunner-server/informer/v6"
+	"github.com/roadrunner-server/pool/v2/state/process"
+	"github.com/roadrunner-server/roadrunner/v2025/container"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/rpctest"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeInformer struct {
+	mu      sync.Mutex
+	workers map[string][]string
+	// the pools ignoring the scaling, e.g. in the debug mode
+	fixed []string
+}
+
+func (f *fakeInformer) set(plugin string, states ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.workers[plugin] = states
+}
+
+func (f *fakeInformer) List(_ bool, list *[]string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for plugin := range f.workers {
+		*list = append(*list, plugin)
+	}
+
+	return nil
+}
+
+func (f *fakeInformer) Workers(plugin string, list *informer.WorkerList) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, st := range f.workers[plugin] {
+		list.Workers = append(list.Workers, &process.State{Pid: int64(i + 1), StatusStr: st})
+	}
+
+	return nil
+}
+
+func (f *fakeInformer) AddWorker(plugin string, done *bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !slices.Contains(f.fixed, plugin) {
+		f.workers[plugin] = append(f.workers[plugin], "ready")
+	}
+
+	*done = true
+
+	return nil
+}
+
+func (f *fakeInformer) RemoveWorker(plugin string, done *bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !slices.Contains(f.fixed, plugin) && len(f.workers[plugin]) > 0 {
+		f.workers[plugin] = f.workers[plugin][1:]
+	}
+
+	*done = true
+
+	return nil
+}
+
+// startStatus plays the status plugin, the plugins from the list are unhealthy.
+func startStatus(t *testing.T, unhealthy ...string) string {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, p := range unhealthy {
+			if r.URL.Path == "/health" && r.URL.Query().Get("plugin") == p {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return strings.TrimPrefix(srv.URL, "http://")
+}
+
+func newChecker(t *testing.T, statusAddr, rpcAddr string, plugins ...string) *healthChecker {
+	t.Helper()
+
+	v := viper.New()
+	if statusAddr != "" {
+		v.Set("status.address", statusAddr)
+	}
+
+	if rpcAddr != "" {
+		v.Set("rpc.listen", rpcAddr)
+	}
+
+	return newHealthChecker(v, &container.Config{WatchdogCritical: plugins}, time.Second*5, true)
+}
+
+func TestHealthCheckerDisabled(t *testing.T) {
+	h := newChecker(t, "", "")
+	assert.Nil(t, h)
+	assert.True(t, h.healthy())
+}
+
+func TestHealthCheckerHealthy(t *testing.T) {
+	fi := &fakeInformer{workers: map[string][]string{"http": {"working", "ready"}, "jobs": {"working"}}}
+	h := newChecker(t, startStatus(t), rpctest.Serve(t, map[string]any{"informer": fi}), "http", "jobs", "kv")
+
+	require.NoError(t, h.check())
+	assert.True(t, h.healthy())
+}
+
+func TestHealthCheckerStatus(t *testing.T) {
+	h := newChecker(t, startStatus(t, "jobs"), "", "http", "jobs")
+
+	err := h.check()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "plugin jobs is unhealthy: health check responded with 503")
+
+	assert.False(t, h.healthy())
+	assert.True(t, h.paused)
+}
+
+func TestHealthCheckerWorkers(t *testing.T) {
+	fi := &fakeInformer{workers: map[string][]string{"http": {"invalid", "stopped"}, "grpc": {}}}
+	rpcAddr := rpctest.Serve(t, map[string]any{"informer": fi})
+
+	err := newChecker(t, "", rpcAddr, "http").check()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "plugin http is unhealthy: no workers ready or working (invalid, stopped)")
+
+	err = newChecker(t, "", rpcAddr, "grpc").check()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "plugin grpc is unhealthy: no workers")
+
+	// not critical
+	require.NoError(t, newChecker(t, "", rpcAddr, "kv").check())
+}
+
+func TestHealthCheckerRecovered(t *testing.T) {
+	fi := &fakeInformer{workers: map[string][]string{"http": {"errored"}}}
+	h := newChecker(t, "", rpctest.Serve(t, map[string]any{"informer": fi}), "http")
+
+	assert.False(t, h.healthy())
+
+	fi.set("http", "ready")
+	assert.True(t, h.healthy())
+	assert.False(t, h.paused)
+}
@@ -0,0 +1,120 @@
+package serve
+
+import (
+	"fmt"
+	"net/rpc"
+	"slices"
+	"strings"
+	"time"
+
+	jobsv1 "github.com/roadrunner-server/api-go/v6/jobs/v1"
+	goridgeRpc "github.com/roadrunner-server/goridge/v4/pkg/rpc"
+	"github.com/roadrunner-server/informer/v6"
+	"github.com/roadrunner-server/roadrunner/v2025/container"
+	internalRpc "github.com/roadrunner-server/roadrunner/v2025/internal/rpc"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/sdnotify"
+	"github.com/spf13/viper"
+)
+
+const (
+	// how long the server status might be collected
+	statusTimeout = time.Second * 5
+
+	statusServing        = "serving"
+	informerList         = "informer.List"
+	informerWorkers      = "informer.Workers"
+	informerAddWorker    = "informer.AddWorker"
+	informerRemoveWorker = "informer.RemoveWorker"
+	jobsList             = "jobs.List"
+	resetterReset        = "resetter.Reset"
+)
+
+// notify sends the states to the service manager in a single message.
+func notify(states ...sdnotify.State) {
+	_, _ = sdnotify.SdNotify(sdnotify.Join(states...))
+}
+
+// watchdogInterval returns how often the service manager is pinged: endure.watchdog_sec, or a half of the watchdog
+// timeout set by the service manager (WatchdogSec=) as recommended by sd_watchdog_enabled(3).
+func watchdogInterval(cfg *container.Config) (time.Duration, error) {
+	if cfg.WatchdogSec > 0 {
+		return time.Duration(cfg.WatchdogSec) * time.Second, nil
+	}
+
+	timeout, err := sdnotify.WatchdogTimeout()
+	if err != nil {
+		return 0, err
+	}
+
+	return timeout / 2, nil
+}
+
+// notifyStatus sends the summary of the running server, e.g. "serving, 8 http workers, 3 pipelines", shown by
+// `systemctl status`. The summary is collected via RPC, only "serving" is reported without it.
+func notifyStatus(v *viper.Viper) {
+	go notify(sdnotify.Status(serverStatus(v)))
+}
+
+func serverStatus(v *viper.Viper) string {
+	if !v.IsSet("rpc") {
+		return statusServing
+	}
+
+	conn, err := internalRpc.Dialer(v.GetString("rpc.listen"))
+	if err != nil {
+		return statusServing
+	}
+
+	// the server might be stopping, the status is not worth waiting for
+	_ = conn.SetDeadline(time.Now().Add(statusTimeout))
+
+	client := rpc.NewClientWithCodec(goridgeRpc.NewClientCodec(conn))
+	defer func() { _ = client.Close() }()
+
+	var plugins []string
+	if err = client.Call(informerList, true, &plugins); err != nil {
+		return statusServing
+	}
+
+	workers := make(map[string]int, len(plugins))
+	for _, plugin := range plugins {
+		list := &informer.WorkerList{}
+		if client.Call(informerWorkers, plugin, &list) == nil {
+			workers[plugin] = len(list.Workers)
+		}
+	}
+
+	pipelines := -1
+	if v.IsSet("jobs") {
+		resp := &jobsv1.Pipelines{}
+		if client.Call(jobsList, &jobsv1.Empty{}, resp) == nil {
+			pipelines = len(resp.GetPipelines())
+		}
+	}
+
+	return formatStatus(workers, pipelines)
+}
+
+// formatStatus lists the plugins with workers in order, the pipelines are omitted when negative.
+func formatStatus(workers map[string]int, pipelines int) string {
+	parts := []string{statusServing}
+
+	plugins := make([]string, 0, len(workers))
+	for plugin, n := range workers {
+		if n > 0 {
+			plugins = append(plugins, plugin)
+		}
+	}
+
+	slices.Sort(plugins)
+
+	for _, plugin := range plugins {
+		parts = append(parts, fmt.Sprintf("%d %s workers", workers[plugin], plugin))
+	}
+
+	if pipelines >= 0 {
+		parts = append(parts, fmt.Sprintf("%d pipelines", pipelines))
+	}
+
+	return strings.Join(parts, ", ")
+}
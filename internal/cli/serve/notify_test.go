@@ -0,0 +1,13 @@
+package serve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatStatus(t *testing.T) {
+	assert.Equal(t, "serving", formatStatus(nil, -1))
+	assert.Equal(t, "serving, 3 pipelines", formatStatus(map[string]int{"jobs": 0}, 3))
+	assert.Equal(t, "serving, 2 grpc workers, 8 http workers, 0 pipelines", formatStatus(map[string]int{"http": 8, "grpc": 2}, 0))
+}
@@ -0,0 +1,34 @@
+package serve
+
+import (
+	"github.com/roadrunner-server/roadrunner/v2025/internal/handoff"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/pidfile"
+)
+
+// openPidFile creates and locks the pid file, the file locked by the previous process is taken over on the graceful
+// restart. Nil is returned when the pid file is not configured.
+func openPidFile(path string) (*pidfile.File, error) {
+	if path == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	name := "pidfile:" + path
+
+	var pf *pidfile.File
+
+	if f := handoff.Inherited(name); f != nil {
+		// the previous process owns the file until this one is ready
+		pf = pidfile.Adopt(path, f)
+	} else {
+		// refuse the second instance before any plugin is started
+		var err error
+		if pf, err = pidfile.Create(path); err != nil {
+			return nil, err
+		}
+	}
+
+	// passed to the new process on the graceful restart, so the lock is never released
+	handoff.Register(name, pf.File())
+
+	return pf, nil
+}
@@ -0,0 +1,27 @@
+package serve
+
+import (
+	"fmt"
+
+	"github.com/roadrunner-server/roadrunner/v2025/container"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/debug"
+)
+
+// startProfiler starts the continuous profiler (endure.debug.profiler), nil is returned when it's disabled.
+func startProfiler(cfg *container.Config, silent bool) (*debug.Profiler, error) {
+	if !cfg.Debug.Profiler.Enabled {
+		return nil, nil //nolint:nilnil
+	}
+
+	p := debug.NewProfiler(&cfg.Debug.Profiler, func(msg string) {
+		log(msg, silent)
+	})
+
+	if err := p.Start(); err != nil {
+		return nil, err
+	}
+
+	log(fmt.Sprintf("[INFO] profiler: capturing the profiles every %s into %s", cfg.Debug.Profiler.Interval, cfg.Debug.Profiler.Dir), silent)
+
+	return p, nil
+}
@@ -0,0 +1,78 @@
+//go:build !windows
+
+package serve
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/roadrunner-server/errors"
+	"github.com/roadrunner-server/roadrunner/v2025/container"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/handoff"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/meta"
+)
+
+// how long the new binary has to print its version
+const versionTimeout = time.Second * 10
+
+type handoffResult struct {
+	pid int
+	err error
+}
+
+// gracefulRestart starts the new process with the same arguments, passing it the handoff listeners, and waits until
+// it's ready. The current process keeps serving in the meantime and when the new process fails to start.
+func gracefulRestart(ctx context.Context, cfg *container.Config, silent bool, res chan<- *handoffResult) {
+	executable, err := os.Executable()
+	if err != nil {
+		res <- &handoffResult{err: err}
+		return
+	}
+
+	if cfg.RestartMode == container.RestartUpgrade {
+		executable = cmp.Or(cfg.UpgradeBinary, executable)
+
+		// the binary which can't even start (e.g. built for another platform) is rejected right away
+		version, errV := binaryVersion(ctx, executable)
+		if errV != nil {
+			res <- &handoffResult{err: errV}
+			return
+		}
+
+		log(fmt.Sprintf("[INFO] upgrading RoadRunner %s to: %s", meta.Version(), version), silent)
+	}
+
+	// the plugins bind their sockets on their own, only the listeners created via handoff.Listen are inherited
+	log("[WARN] the plugins listeners are not passed to the new process, it binds the ports again: the connections not yet accepted by the current process are reset when it stops", silent)
+
+	p, err := handoff.Start(executable, os.Args[1:], os.Environ())
+	if err != nil {
+		res <- &handoffResult{err: err}
+		return
+	}
+
+	log(fmt.Sprintf("[INFO] new process started, PID: %d, waiting %s for it to become ready", p.Pid(), cfg.RestartTimeout), silent)
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.RestartTimeout)
+	defer cancel()
+
+	res <- &handoffResult{pid: p.Pid(), err: p.WaitReady(ctx)}
+}
+
+// binaryVersion runs `rr --version`.
+func binaryVersion(ctx context.Context, executable string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, versionTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, executable, "--version").CombinedOutput() //nolint:gosec
+	if err != nil {
+		return "", errors.Errorf("new binary %s is not runnable: %v: %s", executable, err, strings.TrimSpace(string(out)))
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
@@ -1,48 +1,126 @@
 package stop
 
 import (
+	"cmp"
+	stderr "errors"
 	"log"
 	"os"
-	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/roadrunner-server/errors"
+	"github.com/roadrunner-server/roadrunner/v2025/container"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/pidfile"
 	"github.com/roadrunner-server/roadrunner/v2025/internal/sdnotify"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 const (
-	// sync with root.go
-	pidFileName string = ".pid"
+	// how often the process is checked while waiting for it to exit
+	pollInterval = time.Millisecond * 100
+	// how long to wait for the process after SIGKILL
+	killTimeout = time.Second * 5
+	// the default timeout is the grace period plus this margin: the server exits a bit later than its plugins stop
+	exitMargin = time.Second * 5
 )
 
-// NewCommand creates `serve` command.
-func NewCommand(silent *bool, force *bool) *cobra.Command {
-	return &cobra.Command{
+// Exit codes of the stop command.
+const (
+	// ExitCodeStopped - the process exited gracefully.
+	ExitCodeStopped = 0
+	// ExitCodeError - the process was not signaled, e.g. no pid file.
+	ExitCodeError = 1
+	// ExitCodeStale - the pid file is stale: the process is gone or the PID is reused by another binary.
+	ExitCodeStale = 2
+	// ExitCodeTimeout - the process is still running after the timeout.
+	ExitCodeTimeout = 3
+	// ExitCodeKilled - the process didn't exit within the timeout and was killed (--force).
+	ExitCodeKilled = 4
+)
+
+// ExitError is returned with the exit code describing the outcome of the stop.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode is the exit code of the process.
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+// NewCommand creates `stop` command.
+func NewCommand(cfgFile *string, override *[]string, pidFile *string, silent *bool, force *bool) *cobra.Command {
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
 		Use:   "stop",
 		Short: "Stop RoadRunner server",
+		Long: `Stop RoadRunner server started with the pid file (-p flag or endure.pid_file): send SIGTERM to the process from
+the pid file and wait until it exits. With --force the process is killed (SIGKILL) when it doesn't exit within the timeout.
+
+Exit codes:
+  0 - the server stopped
+  1 - the server was not signaled, e.g. the pid file is missing
+  2 - the pid file is stale: the process is not running or the PID belongs to another program
+  3 - the server is still running after the timeout
+  4 - the server was killed after the timeout (--force)`,
 		RunE: func(*cobra.Command, []string) error {
 			const op = errors.Op("rr_stop")
 
-			_, _ = sdnotify.SdNotify(sdnotify.Stopping)
-			data, err := os.ReadFile(pidFileName)
-			if err != nil {
-				return errors.Errorf("%v, to create a .pid file, you must run RR with the following options: './rr serve -p'", err)
+			if timeout < 0 {
+				return errors.E(op, errors.Str("--timeout should not be negative"))
+			}
+
+			cfg := containerConfig(cfgFile, override, *silent)
+			if timeout == 0 {
+				timeout = cfg.GracePeriod + exitMargin
 			}
 
-			pid, err := strconv.Atoi(string(data))
+			var flagPath string
+			if pidFile != nil {
+				flagPath = *pidFile
+			}
+
+			// the same precedence as in serve
+			path := cmp.Or(flagPath, cfg.PidFile, pidfile.DefaultPath)
+
+			_, _ = sdnotify.SdNotify(sdnotify.Stopping)
+			pid, err := pidfile.Read(path)
 			if err != nil {
+				if os.IsNotExist(err) {
+					return errors.Errorf("%v, to create a pid file, you must run RR with the following options: './rr serve -p' or set endure.pid_file", err)
+				}
+
 				return errors.E(op, err)
 			}
 
+			if err = pidfile.Check(path, pid); err != nil {
+				// the file is left by the crashed process, nobody else removes it. The locked file has a live owner, it's
+				// never removed.
+				if errR := pidfile.RemoveStale(path); stderr.Is(errR, pidfile.ErrLocked) {
+					err = errors.Errorf("%v, but the pid file %s is locked by a running process", err, path)
+				}
+
+				return &ExitError{Code: ExitCodeStale, Err: errors.E(op, err)}
+			}
+
 			process, err := os.FindProcess(pid)
 			if err != nil {
 				return errors.E(op, err)
 			}
 
 			if !*silent {
-				log.Printf("stopping process with PID: %d", pid)
+				log.Printf("stopping process with PID: %d, timeout: %s", pid, timeout)
 			}
 
 			err = process.Signal(syscall.SIGTERM)
@@ -50,16 +128,78 @@ func NewCommand(silent *bool, force *bool) *cobra.Command {
 				return errors.E(op, err)
 			}
 
-			if *force {
-				// RR may lose the signal if we immediately send it
-				time.Sleep(time.Second)
-				err = process.Signal(syscall.SIGTERM)
-				if err != nil {
-					return errors.E(op, err)
+			start := time.Now()
+			if waitExit(pid, timeout) {
+				if !*silent {
+					log.Printf("process with PID %d stopped in %s", pid, time.Since(start).Round(time.Millisecond))
 				}
+
+				return nil
 			}
 
-			return nil
+			if !*force {
+				return &ExitError{Code: ExitCodeTimeout, Err: errors.E(op, errors.Errorf("process with PID %d is still running after %s, use --force to kill it", pid, timeout))}
+			}
+
+			if !*silent {
+				log.Printf("process with PID %d didn't stop in %s, killing it", pid, timeout)
+			}
+
+			err = process.Kill()
+			if err != nil {
+				return errors.E(op, err)
+			}
+
+			if !waitExit(pid, killTimeout) {
+				return &ExitError{Code: ExitCodeTimeout, Err: errors.E(op, errors.Errorf("process with PID %d is still running after SIGKILL", pid))}
+			}
+
+			return &ExitError{Code: ExitCodeKilled, Err: errors.E(op, errors.Errorf("process with PID %d was killed after %s", pid, timeout))}
 		},
 	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "time to wait for the server to exit (default endure.grace_period + 5s)")
+
+	return cmd
+}
+
+// containerConfig returns the endure configuration: the grace period and the pid file of the server.
+func containerConfig(cfgFile *string, override *[]string, silent bool) *container.Config {
+	var flags []string
+	if override != nil {
+		flags = *override
+	}
+
+	if cfgFile != nil {
+		cfg, err := container.NewConfig(*cfgFile, flags...)
+		if err == nil {
+			return cfg
+		}
+
+		// the broken configuration should not prevent the server from being stopped
+		if !silent {
+			log.Printf("failed to read the configuration, using the defaults: %v", err)
+		}
+	}
+
+	cfg, _ := container.ParseConfig(viper.New())
+
+	return cfg
+}
+
+// waitExit waits for the process to exit, returns false on timeout.
+func waitExit(pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		if !pidfile.Alive(pid) {
+			return true
+		}
+
+		if time.Now().After(deadline) {
+			return false
+		}
+
+		time.Sleep(pollInterval)
+	}
 }
@@ -1,4 +1,5 @@
 // Package stop implements the "stop" command that gracefully stops the
 // RoadRunner server by sending SIGTERM to the process identified by the
-// .pid file.
+// .pid file and waiting for it to exit, killing it with --force after the
+// timeout. The outcome is reported with the exit code.
 package stop
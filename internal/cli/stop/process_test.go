@@ -0,0 +1,248 @@
+//go:build !windows
+
+package stop_test
+
+import (
+	"bufio"
+	stderr "errors"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/roadrunner-server/roadrunner/v2025/internal/cli/stop"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/pidfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// the test binary plays the server: it locks the pid file like serve does and exits on SIGTERM or ignores it
+const childEnv = "RR_STOP_TEST_CHILD"
+
+// the pid file of the fake server, locked until it exits
+var pidFile *pidfile.File //nolint:gochecknoglobals
+
+func TestMain(m *testing.M) {
+	mode := os.Getenv(childEnv)
+	if mode != "" {
+		var err error
+		if pidFile, err = pidfile.Create(".pid"); err != nil {
+			os.Exit(2)
+		}
+	}
+
+	switch mode {
+	case "exit":
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGTERM)
+		_, _ = os.Stdout.WriteString("ready\n")
+		<-sig
+		// the graceful shutdown
+		time.Sleep(time.Millisecond * 200)
+		os.Exit(0)
+	case "ignore":
+		signal.Ignore(syscall.SIGTERM)
+		_, _ = os.Stdout.WriteString("ready\n")
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// startChild starts the process with the pid file in the current directory: the fake server creates it, the file of
+// another program is written here.
+func startChild(t *testing.T, name string, args ...string) *exec.Cmd {
+	t.Helper()
+
+	cmd := exec.Command(name, args...)
+	cmd.Env = append(os.Environ(), childEnv+"="+os.Getenv("CHILD_MODE"))
+
+	stdout, err := cmd.StdoutPipe()
+	require.NoError(t, err)
+	require.NoError(t, cmd.Start())
+
+	// reap the process, the zombie is still signaled
+	done := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(done)
+	}()
+
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		<-done
+	})
+
+	if name != os.Args[0] {
+		require.NoError(t, os.WriteFile(".pid", []byte(strconv.Itoa(cmd.Process.Pid)), 0o600))
+		return cmd
+	}
+
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "ready\n", line)
+
+	return cmd
+}
+
+func startServer(t *testing.T, mode string) *exec.Cmd {
+	t.Helper()
+	t.Setenv("CHILD_MODE", mode)
+
+	return startChild(t, os.Args[0], "-test.run=^$")
+}
+
+func runStop(t *testing.T, cfg string, force bool, args ...string) error {
+	t.Helper()
+
+	cmd := stop.NewCommand(&cfg, &[]string{}, toPtr(""), toPtr(true), &force)
+	cmd.SetArgs(args)
+
+	return cmd.Execute()
+}
+
+func exitCode(t *testing.T, err error) int {
+	t.Helper()
+
+	var ee *stop.ExitError
+	require.True(t, stderr.As(err, &ee), "unexpected error: %v", err)
+
+	return ee.ExitCode()
+}
+
+func alive(pid int) bool {
+	return syscall.Kill(pid, 0) == nil
+}
+
+func writeConfig(t *testing.T, grace string) string {
+	t.Helper()
+
+	cfg := filepath.Join(t.TempDir(), ".rr.yaml")
+	require.NoError(t, os.WriteFile(cfg, []byte("version: \"3\"\nendure:\n  grace_period: "+grace+"\n"), 0o600))
+
+	return cfg
+}
+
+func TestStopGraceful(t *testing.T) {
+	t.Chdir(t.TempDir())
+	srv := startServer(t, "exit")
+
+	start := time.Now()
+	err := runStop(t, writeConfig(t, "10s"), false)
+	require.NoError(t, err)
+
+	// waits for the graceful shutdown
+	assert.GreaterOrEqual(t, time.Since(start), time.Millisecond*200)
+	assert.Eventually(t, func() bool { return !alive(srv.Process.Pid) }, time.Second, time.Millisecond*10)
+}
+
+func TestStopTimeout(t *testing.T) {
+	t.Chdir(t.TempDir())
+	srv := startServer(t, "ignore")
+
+	// the timeout defaults to the grace period with the margin for the exit
+	err := runStop(t, writeConfig(t, "300ms"), false)
+	require.Error(t, err)
+	assert.Equal(t, stop.ExitCodeTimeout, exitCode(t, err))
+	assert.Contains(t, err.Error(), "is still running after 5.3s, use --force to kill it")
+
+	// without --force the process is never killed
+	assert.True(t, alive(srv.Process.Pid))
+}
+
+func TestStopForce(t *testing.T) {
+	t.Chdir(t.TempDir())
+	srv := startServer(t, "ignore")
+
+	err := runStop(t, writeConfig(t, "10s"), true, "--timeout", "300ms")
+	require.Error(t, err)
+	assert.Equal(t, stop.ExitCodeKilled, exitCode(t, err))
+	assert.Contains(t, err.Error(), "was killed after 300ms")
+
+	assert.Eventually(t, func() bool { return !alive(srv.Process.Pid) }, time.Second, time.Millisecond*10)
+}
+
+func TestStopStalePid(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	// the PID of the exited process
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	require.NoError(t, cmd.Run())
+	require.NoError(t, os.WriteFile(".pid", []byte(strconv.Itoa(cmd.Process.Pid)), 0o600))
+
+	err := runStop(t, writeConfig(t, "1s"), false)
+	require.Error(t, err)
+	assert.Equal(t, stop.ExitCodeStale, exitCode(t, err))
+	assert.Contains(t, err.Error(), "is not running")
+
+	assert.NoFileExists(t, ".pid")
+}
+
+// the pid file locked by a running process is never removed, even if the PID in it is not running
+func TestStopLockedStalePid(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	require.NoError(t, cmd.Run())
+	require.NoError(t, os.WriteFile(".pid", []byte(strconv.Itoa(cmd.Process.Pid)), 0o600))
+
+	f, err := os.Open(".pid")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+	require.NoError(t, syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB))
+
+	err = runStop(t, writeConfig(t, "1s"), false)
+	require.Error(t, err)
+	assert.Equal(t, stop.ExitCodeStale, exitCode(t, err))
+	assert.Contains(t, err.Error(), "the pid file .pid is locked by a running process")
+
+	assert.FileExists(t, ".pid")
+}
+
+// the PID of another program is never signaled, its pid file is not locked
+func TestStopReusedPid(t *testing.T) {
+	sleep, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("no sleep binary")
+	}
+
+	t.Chdir(t.TempDir())
+	other := startChild(t, sleep, "60")
+
+	err = runStop(t, writeConfig(t, "1s"), true)
+	require.Error(t, err)
+	assert.Equal(t, stop.ExitCodeStale, exitCode(t, err))
+	assert.Contains(t, err.Error(), ".pid is not locked, PID")
+	assert.Contains(t, err.Error(), "belongs to another program")
+
+	assert.True(t, alive(other.Process.Pid))
+	assert.NoFileExists(t, ".pid")
+}
+
+func TestStopNoPidFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	err := runStop(t, writeConfig(t, "1s"), false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "./rr serve -p")
+
+	var ee *stop.ExitError
+	assert.False(t, stderr.As(err, &ee))
+}
+
+func TestStopConfiguredPidFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	startServer(t, "exit")
+	require.NoError(t, os.Rename(".pid", "server.pid"))
+
+	cfg := filepath.Join(t.TempDir(), ".rr.yaml")
+	require.NoError(t, os.WriteFile(cfg, []byte("version: \"3\"\nendure:\n  grace_period: 10s\n  pid_file: server.pid\n"), 0o600))
+
+	err := runStop(t, cfg, false)
+	require.NoError(t, err)
+}
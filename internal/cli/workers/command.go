@@ -2,19 +2,16 @@ package workers
 
 import (
 	"fmt"
-	"net/rpc"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	"github.com/roadrunner-server/api-plugins/v6/jobs"
 	internalRpc "github.com/roadrunner-server/roadrunner/v2025/internal/rpc"
 
-	tm "github.com/buger/goterm"
 	"github.com/fatih/color"
+	"github.com/gdamore/tcell/v2"
 	"github.com/roadrunner-server/errors"
-	"github.com/roadrunner-server/informer/v6"
 	"github.com/spf13/cobra"
 )
 
@@ -22,11 +19,13 @@ import (
 func NewCommand(cfgFile *string, override *[]string) *cobra.Command { //nolint:funlen
 	// interactive workers updates
 	var interactive bool
+	// output format
+	var output string
 
 	cmd := &cobra.Command{
 		Use:   "workers",
 		Short: "Show information about active RoadRunner workers",
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			const (
 				op           = errors.Op("handle_workers_command")
 				informerList = "informer.List"
@@ -36,6 +35,21 @@ func NewCommand(cfgFile *string, override *[]string) *cobra.Command { //nolint:f
 				return errors.E(op, errors.Str("no configuration file provided"))
 			}
 
+			switch output {
+			case outputTable, outputJSON, outputYAML, outputCSV:
+			default:
+				return errors.E(op, errors.Errorf("unknown output format %q (allowed: table, json, yaml, csv)", output))
+			}
+
+			if interactive && output != outputTable {
+				return errors.E(op, errors.Str("interactive mode supports only the table output"))
+			}
+
+			out, errOut := cmd.OutOrStdout(), cmd.ErrOrStderr()
+			if colorsDisabled(out) {
+				color.NoColor = true
+			}
+
 			client, err := internalRpc.NewClient(*cfgFile, *override)
 			if err != nil {
 				return err
@@ -51,30 +65,24 @@ func NewCommand(cfgFile *string, override *[]string) *cobra.Command { //nolint:f
 			}
 
 			if !interactive {
-				showWorkers(plugins, client)
-				return nil
+				return render(out, errOut, output, collect(plugins, client))
 			}
 
-			oss := make(chan os.Signal, 1)
-			signal.Notify(oss, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
-
-			tm.Clear()
+			screen, err := tcell.NewScreen()
+			if err != nil {
+				return errors.E(op, err)
+			}
 
-			tt := time.NewTicker(time.Second)
-			defer tt.Stop()
+			if err = screen.Init(); err != nil {
+				return errors.E(op, err)
+			}
 
-			for {
-				select {
-				case <-oss:
-					return nil
+			defer screen.Fini()
 
-				case <-tt.C:
-					tm.MoveCursor(1, 1)
-					tm.Flush()
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
 
-					showWorkers(plugins, client)
-				}
-			}
+			return newDashboard(screen, client, plugins, os.Getenv("NO_COLOR") != "").run(ctx, time.Second)
 		},
 	}
 
@@ -83,61 +91,15 @@ func NewCommand(cfgFile *string, override *[]string) *cobra.Command { //nolint:f
 		"interactive",
 		"i",
 		false,
-		"render interactive workers table",
+		"run the interactive workers dashboard",
 	)
 
-	return cmd
-}
-
-func showWorkers(plugins []string, client *rpc.Client) {
-	const (
-		informerWorkers = "informer.Workers"
-		informerJobs    = "informer.Jobs"
-		// this is only one exception to Render the workers, service plugin has the same workers as other plugins,
-		// but they are RAW processes and needs to be handled in a different way. We don't need a special RPC call, but
-		// need a special render method.
-		servicePluginName = "service"
+	cmd.Flags().StringVar(
+		&output,
+		"output",
+		outputTable,
+		"output format: table, json, yaml or csv",
 	)
 
-	for _, plugin := range plugins {
-		list := &informer.WorkerList{}
-
-		if err := client.Call(informerWorkers, plugin, &list); err != nil {
-			// this is a special case, when we can't get workers list, we need to render an error message
-			_ = WorkerTable(os.Stdout, list.Workers, fmt.Errorf("failed to receive information about %s plugin: %w", plugin, err)).Render()
-			continue
-		}
-
-		if len(list.Workers) == 0 {
-			continue
-		}
-
-		if plugin == servicePluginName {
-			fmt.Printf("Workers of [%s]:\n", color.HiYellowString(plugin))
-			_ = ServiceWorkerTable(os.Stdout, list.Workers).Render()
-
-			continue
-		}
-
-		fmt.Printf("Workers of [%s]:\n", color.HiYellowString(plugin))
-
-		_ = WorkerTable(os.Stdout, list.Workers, nil).Render()
-	}
-
-	for _, plugin := range plugins {
-		var jst []*jobs.State
-
-		if err := client.Call(informerJobs, plugin, &jst); err != nil {
-			_ = JobsTable(os.Stdout, jst, fmt.Errorf("failed to receive information about %s plugin: %w", plugin, err)).Render()
-			continue
-		}
-
-		// eq to nil
-		if len(jst) == 0 {
-			continue
-		}
-
-		fmt.Printf("Jobs of [%s]:\n", color.HiYellowString(plugin))
-		_ = JobsTable(os.Stdout, jst, nil).Render()
-	}
+	return cmd
 }
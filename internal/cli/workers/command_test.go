@@ -1,11 +1,21 @@
 package workers_test
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"testing"
 
+	"github.com/roadrunner-server/api-plugins/v6/jobs"
+	"github.com/roadrunner-server/informer/v6"
+	"github.com/roadrunner-server/pool/v2/state/process"
 	"github.com/roadrunner-server/roadrunner/v2025/internal/cli/workers"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/rpctest"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v3"
 )
 
 func TestCommandProperties(t *testing.T) {
@@ -24,6 +34,7 @@ func TestCommandFlags(t *testing.T) {
 		wantDefault   string
 	}{
 		{giveName: "interactive", wantShorthand: "i", wantDefault: "false"},
+		{giveName: "output", wantShorthand: "", wantDefault: "table"},
 	}
 
 	for _, tt := range cases {
@@ -45,3 +56,139 @@ func TestCommandFlags(t *testing.T) {
 func TestExecution(t *testing.T) {
 	t.Skip("Command execution is not implemented yet")
 }
+
+// fakeInformer mimics the informer plugin RPC: the http plugin has workers and no jobs, the jobs plugin fails to
+// return its workers.
+type fakeInformer struct{}
+
+func (fakeInformer) List(_ bool, list *[]string) error {
+	*list = []string{"http", "jobs"}
+	return nil
+}
+
+func (fakeInformer) Workers(plugin string, list *informer.WorkerList) error {
+	if plugin != "http" {
+		return errors.New("no workers")
+	}
+
+	list.Workers = []*process.State{
+		{Pid: 20, Status: 1, NumExecs: 7, MemoryUsage: 1024, Command: "php worker.php", StatusStr: "ready"},
+		{Pid: 10, Status: 1, NumExecs: 3, MemoryUsage: 2048, Command: "php worker.php", StatusStr: "ready"},
+	}
+
+	return nil
+}
+
+func (fakeInformer) Jobs(plugin string, st *[]*jobs.State) error {
+	if plugin != "jobs" {
+		return nil
+	}
+
+	*st = []*jobs.State{
+		{Pipeline: "emails", Driver: "memory", Queue: "emails", Active: 2, Ready: true, Priority: 10},
+	}
+
+	return nil
+}
+
+func runWorkers(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+
+	cfg := rpctest.Config(t, map[string]any{"informer": fakeInformer{}})
+
+	cmd := workers.NewCommand(&cfg, &[]string{})
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+
+	return stdout.String(), stderr.String(), err
+}
+
+func TestOutputJSON(t *testing.T) {
+	out, _, err := runWorkers(t, "--output", "json")
+	require.NoError(t, err)
+
+	var reports []*workers.Report
+	require.NoError(t, json.Unmarshal([]byte(out), &reports))
+	require.Len(t, reports, 2)
+
+	assert.Equal(t, "http", reports[0].Plugin)
+	require.Len(t, reports[0].Workers, 2)
+	assert.Equal(t, int64(10), reports[0].Workers[0].Pid)
+	assert.Equal(t, int64(20), reports[0].Workers[1].Pid)
+	assert.Empty(t, reports[0].WorkersError)
+
+	assert.Equal(t, "jobs", reports[1].Plugin)
+	assert.Empty(t, reports[1].Workers)
+	assert.Contains(t, reports[1].WorkersError, "no workers")
+	require.Len(t, reports[1].Jobs, 1)
+	assert.Equal(t, "emails", reports[1].Jobs[0].Pipeline)
+	assert.Equal(t, int64(2), reports[1].Jobs[0].Active)
+}
+
+func TestOutputYAML(t *testing.T) {
+	out, _, err := runWorkers(t, "--output", "yaml", "jobs")
+	require.NoError(t, err)
+
+	var reports []map[string]any
+	require.NoError(t, yaml.Unmarshal([]byte(out), &reports))
+	require.Len(t, reports, 1)
+
+	assert.Equal(t, "jobs", reports[0]["plugin"])
+	assert.Contains(t, reports[0]["workers_error"], "no workers")
+}
+
+func TestOutputCSV(t *testing.T) {
+	out, _, err := runWorkers(t, "--output", "csv")
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(bytes.NewBufferString(out)).ReadAll()
+	require.NoError(t, err)
+	// header, 2 workers, 1 pipeline, 1 error
+	require.Len(t, records, 5)
+
+	assert.Equal(t, []string{"plugin", "kind", "pid"}, records[0][:3])
+	assert.Equal(t, []string{"http", "worker", "10"}, records[1][:3])
+	assert.Equal(t, []string{"http", "worker", "20"}, records[2][:3])
+	assert.Equal(t, []string{"jobs", "pipeline"}, records[3][:2])
+	assert.Equal(t, "emails", records[3][10])
+	assert.Equal(t, []string{"jobs", "workers_error"}, records[4][:2])
+	assert.Contains(t, records[4][len(records[4])-1], "no workers")
+}
+
+func TestOutputTable(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	out, errOut, err := runWorkers(t)
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "Workers of [http]:")
+	assert.Contains(t, out, "Jobs of [jobs]:")
+	assert.NotContains(t, out, "ERROR")
+	// no escape sequences when colors are disabled
+	assert.NotContains(t, out, "\x1b[")
+	assert.Contains(t, errOut, "failed to receive information about jobs plugin workers")
+}
+
+func TestOutputUnknownFormat(t *testing.T) {
+	cfg := ".rr.yaml"
+	cmd := workers.NewCommand(&cfg, &[]string{})
+	cmd.SetArgs([]string{"--output", "xml"})
+
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown output format "xml"`)
+}
+
+func TestOutputInteractiveRequiresTable(t *testing.T) {
+	cfg := ".rr.yaml"
+	cmd := workers.NewCommand(&cfg, &[]string{})
+	cmd.SetArgs([]string{"-i", "--output", "json"})
+
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "interactive mode supports only the table output")
+}
@@ -0,0 +1,654 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	resetterReset        = "resetter.Reset"
+	informerRemoveWorker = "informer.RemoveWorker"
+	// number of snapshots kept for the sparklines
+	historySize = 30
+)
+
+// caller is the part of the *rpc.Client used to talk to the RoadRunner.
+type caller interface {
+	Call(serviceMethod string, args any, reply any) error
+}
+
+type sortColumn int
+
+const (
+	sortPID sortColumn = iota
+	sortMemory
+	sortCPU
+	sortExecs
+	sortAge
+)
+
+func (s sortColumn) String() string {
+	switch s {
+	case sortMemory:
+		return "memory"
+	case sortCPU:
+		return "cpu"
+	case sortExecs:
+		return "execs"
+	case sortAge:
+		return "age"
+	default:
+		return "pid"
+	}
+}
+
+// statusFilters are cycled with the `f` key, the empty one shows all workers.
+var statusFilters = []string{"", "ready", "working", "inactive", "invalid", "stopped", "errored"} //nolint:gochecknoglobals
+
+var sparks = []rune("▁▂▃▄▅▆▇█") //nolint:gochecknoglobals
+
+// series holds the last historySize memory and CPU samples of a worker.
+type series struct {
+	memory []float64
+	cpu    []float64
+}
+
+func (s *series) add(memory, cpu float64) {
+	s.memory = appendSample(s.memory, memory)
+	s.cpu = appendSample(s.cpu, cpu)
+}
+
+func appendSample(samples []float64, v float64) []float64 {
+	samples = append(samples, v)
+	if len(samples) > historySize {
+		samples = samples[len(samples)-historySize:]
+	}
+
+	return samples
+}
+
+// actionResult is sent back to the event loop when the reset or remove RPC call finishes.
+type actionResult struct {
+	message string
+	err     error
+}
+
+// dashboard is the full-screen `rr workers -i` view: one tab per plugin with the sortable and filterable workers
+// table, the memory/CPU sparklines and the jobs pipelines.
+type dashboard struct {
+	screen  tcell.Screen
+	client  caller
+	plugins []string
+	noColor bool
+
+	reports []*Report
+	// plugin -> pid -> samples
+	history map[string]map[int64]*series
+
+	tab      int
+	selected int
+	sortBy   sortColumn
+	desc     bool
+	filter   int
+	message  string
+	// the reset or remove waiting for the y/n answer
+	confirm func()
+	results chan actionResult
+	// closed when the event loop exits, the pending actions drop their results
+	done chan struct{}
+}
+
+func newDashboard(screen tcell.Screen, client caller, plugins []string, noColor bool) *dashboard {
+	return &dashboard{
+		screen:  screen,
+		client:  client,
+		plugins: plugins,
+		noColor: noColor,
+		history: make(map[string]map[int64]*series, len(plugins)),
+		results: make(chan actionResult, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+// run refreshes the dashboard every interval until the user quits or the context is canceled.
+func (d *dashboard) run(ctx context.Context, interval time.Duration) error {
+	events := make(chan tcell.Event, 1)
+	quit := make(chan struct{})
+	defer close(quit)
+	defer close(d.done)
+
+	go d.screen.ChannelEvents(events, quit)
+
+	tt := time.NewTicker(interval)
+	defer tt.Stop()
+
+	d.refresh()
+	d.draw()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			if d.handleEvent(ev) {
+				return nil
+			}
+		case res := <-d.results:
+			d.message = res.message
+			if res.err != nil {
+				d.message = res.err.Error()
+			}
+
+			d.refresh()
+		case <-tt.C:
+			d.refresh()
+		}
+
+		d.draw()
+	}
+}
+
+// refresh requests the new snapshot and records the samples for the sparklines.
+func (d *dashboard) refresh() {
+	d.reports = collect(d.plugins, d.client)
+
+	for _, r := range d.reports {
+		hist, ok := d.history[r.Plugin]
+		if !ok {
+			hist = make(map[int64]*series, len(r.Workers))
+			d.history[r.Plugin] = hist
+		}
+
+		alive := make(map[int64]struct{}, len(r.Workers))
+		for _, w := range r.Workers {
+			alive[w.Pid] = struct{}{}
+
+			s, ok := hist[w.Pid]
+			if !ok {
+				s = &series{}
+				hist[w.Pid] = s
+			}
+
+			s.add(float64(w.MemoryUsage), w.CPUPercent)
+		}
+
+		// forget the removed workers
+		for pid := range hist {
+			if _, ok := alive[pid]; !ok {
+				delete(hist, pid)
+			}
+		}
+	}
+}
+
+// handleEvent applies the key binding or the resize, it returns true when the user wants to quit.
+func (d *dashboard) handleEvent(ev tcell.Event) bool {
+	switch e := ev.(type) {
+	case *tcell.EventResize:
+		d.screen.Sync()
+	case *tcell.EventKey:
+		if d.confirm != nil {
+			return d.answer(e)
+		}
+
+		switch e.Key() {
+		case tcell.KeyCtrlC, tcell.KeyEscape:
+			return true
+		case tcell.KeyRight, tcell.KeyTab:
+			d.switchTab(1)
+		case tcell.KeyLeft, tcell.KeyBacktab:
+			d.switchTab(-1)
+		case tcell.KeyUp:
+			d.selected = max(d.selected-1, 0)
+		case tcell.KeyDown:
+			d.selected++
+		case tcell.KeyDelete:
+			d.removeWorker()
+		case tcell.KeyRune:
+			return d.handleRune(e.Rune())
+		default:
+		}
+	}
+
+	return false
+}
+
+func (d *dashboard) handleRune(r rune) bool {
+	switch r {
+	case 'q':
+		return true
+	case 's':
+		d.sortBy = (d.sortBy + 1) % (sortAge + 1)
+	case 'S':
+		d.desc = !d.desc
+	case 'f':
+		d.filter = (d.filter + 1) % len(statusFilters)
+		d.selected = 0
+	case 'r':
+		d.reset()
+	case 'x':
+		d.removeWorker()
+	default:
+		if r >= '1' && r <= '9' && int(r-'1') < len(d.plugins) {
+			d.tab = int(r - '1')
+			d.selected = 0
+		}
+	}
+
+	return false
+}
+
+func (d *dashboard) switchTab(delta int) {
+	if len(d.plugins) == 0 {
+		return
+	}
+
+	d.tab = (d.tab + delta + len(d.plugins)) % len(d.plugins)
+	d.selected = 0
+}
+
+func (d *dashboard) currentPlugin() string {
+	if d.tab >= len(d.plugins) {
+		return ""
+	}
+
+	return d.plugins[d.tab]
+}
+
+// ask shows the question in the status line, the action runs only when the user answers 'y'.
+func (d *dashboard) ask(question string, action func()) {
+	d.message = question + " [y/n]"
+	d.confirm = action
+}
+
+// answer runs the action waiting for the confirmation on 'y' and cancels it on any other key, Ctrl+C still quits.
+func (d *dashboard) answer(e *tcell.EventKey) bool {
+	action := d.confirm
+	d.confirm = nil
+
+	switch {
+	case e.Key() == tcell.KeyCtrlC:
+		return true
+	case e.Key() == tcell.KeyRune && (e.Rune() == 'y' || e.Rune() == 'Y'):
+		action()
+	default:
+		d.message = "canceled"
+	}
+
+	return false
+}
+
+// reset resets the workers of the current plugin in the background once confirmed, the result is shown in the status
+// line.
+func (d *dashboard) reset() {
+	plugin := d.currentPlugin()
+	if plugin == "" {
+		return
+	}
+
+	d.ask(fmt.Sprintf("reset all the workers of [%s]?", plugin), func() {
+		d.message = fmt.Sprintf("resetting plugin: [%s]", plugin)
+
+		go func() {
+			var done bool
+			err := d.client.Call(resetterReset, plugin, &done)
+			d.send(actionResult{message: fmt.Sprintf("plugin reset: [%s]", plugin), err: err})
+		}()
+	})
+}
+
+// removeWorker asks the pool of the current plugin to remove one of its workers once confirmed. The informer API
+// doesn't accept the PID, the worker is picked by the pool, not the selected one.
+func (d *dashboard) removeWorker() {
+	plugin := d.currentPlugin()
+	if plugin == "" || plugin == servicePluginName {
+		return
+	}
+
+	d.ask(fmt.Sprintf("remove one worker of [%s]? the pool picks the worker, not the selected one", plugin), func() {
+		d.message = fmt.Sprintf("removing a worker of [%s]", plugin)
+
+		go func() {
+			var done bool
+			err := d.client.Call(informerRemoveWorker, plugin, &done)
+			d.send(actionResult{message: fmt.Sprintf("worker removed: [%s]", plugin), err: err})
+		}()
+	})
+}
+
+// send passes the action result to the event loop, the result is dropped once the dashboard is closed.
+func (d *dashboard) send(res actionResult) {
+	select {
+	case d.results <- res:
+	case <-d.done:
+	}
+}
+
+// workers returns the filtered and sorted workers of the current tab.
+func (d *dashboard) workers(r *Report) []*workerRow {
+	hist := d.history[r.Plugin]
+	rows := make([]*workerRow, 0, len(r.Workers))
+
+	for _, w := range r.Workers {
+		if f := statusFilters[d.filter]; f != "" && w.StatusStr != f {
+			continue
+		}
+
+		row := &workerRow{
+			pid:     w.Pid,
+			status:  w.StatusStr,
+			execs:   w.NumExecs,
+			memory:  w.MemoryUsage,
+			cpu:     w.CPUPercent,
+			created: time.Unix(0, w.Created),
+			command: w.Command,
+		}
+
+		if s, ok := hist[w.Pid]; ok {
+			row.history = s
+		}
+
+		rows = append(rows, row)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if d.desc {
+			return rows[j].less(rows[i], d.sortBy)
+		}
+
+		return rows[i].less(rows[j], d.sortBy)
+	})
+
+	return rows
+}
+
+type workerRow struct {
+	pid     int64
+	status  string
+	execs   uint64
+	memory  uint64
+	cpu     float64
+	created time.Time
+	command string
+	history *series
+}
+
+func (w *workerRow) less(o *workerRow, by sortColumn) bool {
+	switch by {
+	case sortMemory:
+		return w.memory < o.memory
+	case sortCPU:
+		return w.cpu < o.cpu
+	case sortExecs:
+		return w.execs < o.execs
+	case sortAge:
+		// the youngest first
+		return w.created.After(o.created)
+	default:
+		return w.pid < o.pid
+	}
+}
+
+// draw renders the whole screen.
+func (d *dashboard) draw() {
+	d.screen.Clear()
+	width, height := d.screen.Size()
+
+	// tabs
+	x := 0
+	for i, p := range d.plugins {
+		style := tcell.StyleDefault
+		if i == d.tab {
+			style = style.Reverse(true).Bold(true)
+		}
+
+		x += d.print(x, 0, width, style, fmt.Sprintf(" %d:%s ", i+1, p)) + 1
+	}
+
+	y := 2
+
+	var report *Report
+	for _, r := range d.reports {
+		if r.Plugin == d.currentPlugin() {
+			report = r
+			break
+		}
+	}
+
+	switch {
+	case report == nil:
+		d.print(0, y, width, tcell.StyleDefault, "no data yet")
+	case report.Plugin == servicePluginName:
+		y = d.drawServiceWorkers(y, width, report)
+	default:
+		y = d.drawWorkers(y, width, report)
+	}
+
+	if report != nil {
+		d.drawJobs(y+1, width, report)
+	}
+
+	// status and help lines
+	if d.message != "" {
+		d.print(0, height-2, width, d.style(tcell.ColorYellow), d.message)
+	}
+
+	d.print(0, height-1, width, tcell.StyleDefault.Dim(true),
+		"←/→ tab  ↑/↓ select  s sort  S reverse  f filter  r reset plugin  x remove one worker (pool's choice)  q quit")
+
+	d.screen.Show()
+}
+
+func (d *dashboard) drawWorkers(y, width int, r *Report) int {
+	rows := d.workers(r)
+
+	filter := statusFilters[d.filter]
+	if filter == "" {
+		filter = "all"
+	}
+
+	order := "asc"
+	if d.desc {
+		order = "desc"
+	}
+
+	d.print(0, y, width, tcell.StyleDefault, fmt.Sprintf("workers: %d/%d  sort: %s (%s)  filter: %s", len(rows), len(r.Workers), d.sortBy, order, filter))
+	y++
+
+	if r.WorkersError != "" {
+		d.print(0, y, width, d.style(tcell.ColorRed), "failed to receive the workers: "+r.WorkersError)
+		return y + 1
+	}
+
+	columns := []int{8, 10, 10, 10, 8, 16, historySize + 2, historySize}
+	d.row(y, width, columns, tcell.StyleDefault.Bold(true), "PID", "STATUS", "EXECS", "MEMORY", "CPU%", "CREATED", "MEMORY HISTORY", "CPU HISTORY")
+	y++
+
+	d.selected = min(d.selected, max(len(rows)-1, 0))
+
+	for i, w := range rows {
+		style := tcell.StyleDefault
+		if i == d.selected {
+			style = style.Reverse(true)
+		}
+
+		var memHist, cpuHist string
+		if w.history != nil {
+			memHist, cpuHist = sparkline(w.history.memory), sparkline(w.history.cpu)
+		}
+
+		d.row(y, width, columns, style,
+			strconv.FormatInt(w.pid, 10),
+			w.status,
+			renderJobs(w.execs),
+			humanize.Bytes(w.memory),
+			renderCPU(w.cpu),
+			renderAlive(w.created),
+			memHist,
+			cpuHist,
+		)
+
+		// the status column is colored like in the table output
+		if i != d.selected {
+			d.print(columns[0], y, width, d.statusStyle(w.status), w.status)
+		}
+
+		y++
+	}
+
+	return y
+}
+
+func (d *dashboard) drawServiceWorkers(y, width int, r *Report) int {
+	if r.WorkersError != "" {
+		d.print(0, y, width, d.style(tcell.ColorRed), "failed to receive the workers: "+r.WorkersError)
+		return y + 1
+	}
+
+	rows := d.workers(r)
+	columns := []int{8, 10, 8, historySize + 2, historySize + 2, 0}
+	d.row(y, width, columns, tcell.StyleDefault.Bold(true), "PID", "MEMORY", "CPU%", "MEMORY HISTORY", "CPU HISTORY", "COMMAND")
+	y++
+
+	for _, w := range rows {
+		var memHist, cpuHist string
+		if w.history != nil {
+			memHist, cpuHist = sparkline(w.history.memory), sparkline(w.history.cpu)
+		}
+
+		d.row(y, width, columns, tcell.StyleDefault,
+			strconv.FormatInt(w.pid, 10),
+			humanize.Bytes(w.memory),
+			renderCPU(w.cpu),
+			memHist,
+			cpuHist,
+			w.command,
+		)
+		y++
+	}
+
+	return y
+}
+
+func (d *dashboard) drawJobs(y, width int, r *Report) {
+	if r.JobsError != "" {
+		d.print(0, y, width, d.style(tcell.ColorRed), "failed to receive the jobs: "+r.JobsError)
+		return
+	}
+
+	if len(r.Jobs) == 0 {
+		return
+	}
+
+	columns := []int{16, 20, 12, 20, 10, 10, 10}
+	d.row(y, width, columns, tcell.StyleDefault.Bold(true), "STATUS", "PIPELINE", "DRIVER", "QUEUE", "ACTIVE", "DELAYED", "RESERVED")
+	y++
+
+	for _, j := range r.Jobs {
+		style := d.style(tcell.ColorGreen)
+		if !j.Ready {
+			style = d.style(tcell.ColorYellow)
+		}
+
+		d.row(y, width, columns, style,
+			renderReady(j.Ready),
+			j.Pipeline,
+			j.Driver,
+			j.Queue,
+			strconv.FormatInt(j.Active, 10),
+			strconv.FormatInt(j.Delayed, 10),
+			strconv.FormatInt(j.Reserved, 10),
+		)
+		y++
+	}
+}
+
+// row prints the cells using the column widths, the zero width takes the rest of the line.
+func (d *dashboard) row(y, width int, columns []int, style tcell.Style, cells ...string) {
+	x := 0
+	for i, c := range cells {
+		w := width - x
+		if i < len(columns) && columns[i] > 0 {
+			w = columns[i]
+		}
+
+		// fill the whole cell to keep the selection highlighted
+		d.print(x, y, min(x+w, width), style, runewidth.FillRight(runewidth.Truncate(c, w-1, ""), w))
+		x += w
+
+		if x >= width {
+			return
+		}
+	}
+}
+
+// print draws the string from x up to the limit and returns the printed width.
+func (d *dashboard) print(x, y, limit int, style tcell.Style, s string) int {
+	start := x
+	for _, r := range s {
+		w := runewidth.RuneWidth(r)
+		if x+w > limit {
+			break
+		}
+
+		d.screen.SetContent(x, y, r, nil, style)
+		x += w
+	}
+
+	return x - start
+}
+
+func (d *dashboard) style(c tcell.Color) tcell.Style {
+	if d.noColor {
+		return tcell.StyleDefault
+	}
+
+	return tcell.StyleDefault.Foreground(c)
+}
+
+func (d *dashboard) statusStyle(status string) tcell.Style {
+	switch status {
+	case "inactive", "invalid":
+		return d.style(tcell.ColorYellow)
+	case "ready":
+		return d.style(tcell.ColorDarkCyan)
+	case "working":
+		return d.style(tcell.ColorGreen)
+	case "stopped", "errored":
+		return d.style(tcell.ColorRed)
+	default:
+		return tcell.StyleDefault
+	}
+}
+
+// sparkline renders the samples scaled to the maximum value.
+func sparkline(samples []float64) string {
+	var maxV float64
+	for _, v := range samples {
+		maxV = max(maxV, v)
+	}
+
+	var sb strings.Builder
+	for _, v := range samples {
+		idx := 0
+		if maxV > 0 {
+			idx = int(v / maxV * float64(len(sparks)-1))
+		}
+
+		sb.WriteRune(sparks[idx])
+	}
+
+	return sb.String()
+}
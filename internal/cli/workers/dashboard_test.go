@@ -0,0 +1,284 @@
+package workers
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/roadrunner-server/api-plugins/v6/jobs"
+	"github.com/roadrunner-server/informer/v6"
+	"github.com/roadrunner-server/pool/v2/state/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCaller struct {
+	mu      sync.Mutex
+	workers map[string][]*process.State
+	calls   []string
+}
+
+func (f *fakeCaller) Call(method string, args any, reply any) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch method {
+	case informerWorkers:
+		(*reply.(**informer.WorkerList)).Workers = f.workers[args.(string)]
+	case informerJobs:
+		if args.(string) == "jobs" {
+			*reply.(*[]*jobs.State) = []*jobs.State{{Pipeline: "emails", Driver: "memory", Queue: "emails", Ready: true}}
+		}
+	default:
+		f.calls = append(f.calls, method+" "+args.(string))
+		*reply.(*bool) = true
+	}
+
+	return nil
+}
+
+func (f *fakeCaller) called() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.calls...)
+}
+
+func newTestDashboard(t *testing.T) (*dashboard, tcell.SimulationScreen, *fakeCaller) {
+	t.Helper()
+
+	screen := tcell.NewSimulationScreen("UTF-8")
+	require.NoError(t, screen.Init())
+	t.Cleanup(screen.Fini)
+	screen.SetSize(160, 20)
+
+	now := time.Now()
+	client := &fakeCaller{workers: map[string][]*process.State{
+		"http": {
+			{Pid: 1, StatusStr: "ready", NumExecs: 30, MemoryUsage: 300, CPUPercent: 1, Created: now.Add(-time.Hour).UnixNano()},
+			{Pid: 2, StatusStr: "working", NumExecs: 10, MemoryUsage: 100, CPUPercent: 3, Created: now.Add(-time.Minute).UnixNano()},
+			{Pid: 3, StatusStr: "ready", NumExecs: 20, MemoryUsage: 200, CPUPercent: 2, Created: now.UnixNano()},
+		},
+	}}
+
+	d := newDashboard(screen, client, []string{"http", "jobs"}, true)
+	d.refresh()
+
+	return d, screen, client
+}
+
+func lines(screen tcell.SimulationScreen) []string {
+	cells, width, height := screen.GetContents()
+	res := make([]string, height)
+
+	for y := range height {
+		var sb strings.Builder
+		for x := range width {
+			r := cells[y*width+x].Runes
+			if len(r) == 0 {
+				sb.WriteByte(' ')
+				continue
+			}
+
+			sb.WriteRune(r[0])
+		}
+
+		res[y] = strings.TrimRight(sb.String(), " ")
+	}
+
+	return res
+}
+
+func pids(d *dashboard) []int64 {
+	rows := d.workers(d.reports[0])
+	res := make([]int64, 0, len(rows))
+
+	for _, r := range rows {
+		res = append(res, r.pid)
+	}
+
+	return res
+}
+
+func key(r rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+}
+
+func TestDashboardSort(t *testing.T) {
+	d, _, _ := newTestDashboard(t)
+
+	assert.Equal(t, []int64{1, 2, 3}, pids(d))
+
+	d.handleEvent(key('s'))
+	assert.Equal(t, sortMemory, d.sortBy)
+	assert.Equal(t, []int64{2, 3, 1}, pids(d))
+
+	d.handleEvent(key('s'))
+	assert.Equal(t, []int64{1, 3, 2}, pids(d))
+
+	d.handleEvent(key('s'))
+	assert.Equal(t, []int64{2, 3, 1}, pids(d))
+
+	d.handleEvent(key('s'))
+	assert.Equal(t, sortAge, d.sortBy)
+	assert.Equal(t, []int64{3, 2, 1}, pids(d))
+
+	d.handleEvent(key('S'))
+	assert.Equal(t, []int64{1, 2, 3}, pids(d))
+
+	d.handleEvent(key('s'))
+	assert.Equal(t, sortPID, d.sortBy)
+	assert.Equal(t, []int64{3, 2, 1}, pids(d))
+}
+
+func TestDashboardFilter(t *testing.T) {
+	d, screen, _ := newTestDashboard(t)
+
+	d.handleEvent(key('f'))
+	assert.Equal(t, []int64{1, 3}, pids(d))
+
+	d.draw()
+	assert.Contains(t, strings.Join(lines(screen), "\n"), "workers: 2/3  sort: pid (asc)  filter: ready")
+
+	d.handleEvent(key('f'))
+	assert.Equal(t, []int64{2}, pids(d))
+}
+
+func TestDashboardTabs(t *testing.T) {
+	d, screen, _ := newTestDashboard(t)
+
+	d.draw()
+	out := lines(screen)
+	assert.Contains(t, out[0], "1:http")
+	assert.Contains(t, out[0], "2:jobs")
+	assert.Contains(t, strings.Join(out, "\n"), "PID")
+
+	d.handleEvent(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone))
+	assert.Equal(t, "jobs", d.currentPlugin())
+
+	d.draw()
+	assert.Contains(t, strings.Join(lines(screen), "\n"), "emails")
+
+	d.handleEvent(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone))
+	assert.Equal(t, "http", d.currentPlugin())
+
+	d.handleEvent(key('2'))
+	assert.Equal(t, "jobs", d.currentPlugin())
+
+	// no such tab
+	d.handleEvent(key('9'))
+	assert.Equal(t, "jobs", d.currentPlugin())
+}
+
+func TestDashboardSparklines(t *testing.T) {
+	d, screen, client := newTestDashboard(t)
+
+	client.workers["http"][0].MemoryUsage = 600
+	d.refresh()
+	client.workers["http"] = client.workers["http"][:1]
+	d.refresh()
+
+	hist := d.history["http"]
+	require.Len(t, hist, 1)
+	assert.Equal(t, []float64{300, 600, 600}, hist[1].memory)
+
+	d.draw()
+	assert.Contains(t, strings.Join(lines(screen), "\n"), "▄██")
+
+	for range historySize + 5 {
+		d.refresh()
+	}
+
+	assert.Len(t, hist[1].memory, historySize)
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "▁▄█", sparkline([]float64{0, 50, 100}))
+	assert.Equal(t, "▁▁", sparkline([]float64{0, 0}))
+	assert.Empty(t, sparkline(nil))
+}
+
+func TestDashboardResize(t *testing.T) {
+	d, screen, _ := newTestDashboard(t)
+
+	screen.SetSize(60, 10)
+	assert.False(t, d.handleEvent(tcell.NewEventResize(60, 10)))
+	d.draw()
+
+	out := lines(screen)
+	require.Len(t, out, 10)
+	assert.Contains(t, out[9], "tab")
+
+	for _, l := range out {
+		assert.LessOrEqual(t, len([]rune(l)), 60)
+	}
+}
+
+func TestDashboardActions(t *testing.T) {
+	d, _, client := newTestDashboard(t)
+
+	d.handleEvent(key('r'))
+	assert.Equal(t, "reset all the workers of [http]? [y/n]", d.message)
+	d.handleEvent(key('y'))
+	res := <-d.results
+	require.NoError(t, res.err)
+	assert.Equal(t, "plugin reset: [http]", res.message)
+
+	d.handleEvent(tcell.NewEventKey(tcell.KeyDelete, 0, tcell.ModNone))
+	d.handleEvent(key('y'))
+	res = <-d.results
+	require.NoError(t, res.err)
+	assert.Equal(t, "worker removed: [http]", res.message)
+
+	assert.Equal(t, []string{"resetter.Reset http", "informer.RemoveWorker http"}, client.called())
+}
+
+func TestDashboardActionsCanceled(t *testing.T) {
+	d, _, client := newTestDashboard(t)
+
+	d.handleEvent(key('x'))
+	assert.Contains(t, d.message, "remove one worker of [http]?")
+	assert.False(t, d.handleEvent(key('q')))
+	assert.Equal(t, "canceled", d.message)
+
+	d.handleEvent(key('r'))
+	d.handleEvent(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	assert.Equal(t, "canceled", d.message)
+
+	assert.Empty(t, client.called())
+}
+
+// the actions finishing after quit don't block on the results
+func TestDashboardActionsAfterQuit(t *testing.T) {
+	d, _, _ := newTestDashboard(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, d.run(ctx, time.Second))
+
+	sent := make(chan struct{})
+	go func() {
+		for range cap(d.results) + 1 {
+			d.send(actionResult{message: "late"})
+		}
+
+		close(sent)
+	}()
+
+	select {
+	case <-sent:
+	case <-time.After(time.Second * 5):
+		t.Fatal("the action result is blocked after quit")
+	}
+}
+
+func TestDashboardQuit(t *testing.T) {
+	d, _, _ := newTestDashboard(t)
+
+	assert.True(t, d.handleEvent(key('q')))
+	assert.True(t, d.handleEvent(tcell.NewEventKey(tcell.KeyCtrlC, 0, tcell.ModCtrl)))
+	assert.False(t, d.handleEvent(key('z')))
+}
@@ -1,4 +1,4 @@
 // Package workers implements the "workers" command that displays information
-// about active RoadRunner workers and job pipelines via RPC, with support
-// for interactive real-time updates.
+// about active RoadRunner workers and job pipelines via RPC, in the table,
+// JSON, YAML or CSV form, or in the full-screen interactive dashboard.
 package workers
@@ -0,0 +1,255 @@
+package workers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+	"github.com/roadrunner-server/api-plugins/v6/jobs"
+	"github.com/roadrunner-server/errors"
+	"github.com/roadrunner-server/informer/v6"
+	"github.com/roadrunner-server/pool/v2/state/process"
+	"go.yaml.in/yaml/v3"
+)
+
+// output formats
+const (
+	outputTable string = "table"
+	outputJSON  string = "json"
+	outputYAML  string = "yaml"
+	outputCSV   string = "csv"
+)
+
+const (
+	informerWorkers = "informer.Workers"
+	informerJobs    = "informer.Jobs"
+	// this is only one exception to Render the workers, service plugin has the same workers as other plugins,
+	// but they are RAW processes and needs to be handled in a different way. We don't need a special RPC call, but
+	// need a special render method.
+	servicePluginName = "service"
+)
+
+// Report contains the workers and the jobs pipelines of a single plugin. RPC errors are reported per plugin and per
+// call, the data received from the other calls is still available.
+type Report struct {
+	Plugin       string           `json:"plugin"`
+	Workers      []*process.State `json:"workers"`
+	WorkersError string           `json:"workers_error,omitempty"`
+	Jobs         []*jobs.State    `json:"jobs"`
+	JobsError    string           `json:"jobs_error,omitempty"`
+}
+
+// collect requests the workers and the jobs state of every plugin.
+func collect(plugins []string, client caller) []*Report {
+	reports := make([]*Report, 0, len(plugins))
+
+	for _, plugin := range plugins {
+		r := &Report{Plugin: plugin}
+
+		list := &informer.WorkerList{}
+		if err := client.Call(informerWorkers, plugin, &list); err != nil {
+			r.WorkersError = err.Error()
+		} else {
+			r.Workers = list.Workers
+			sort.Slice(r.Workers, func(i, j int) bool {
+				return r.Workers[i].Pid < r.Workers[j].Pid
+			})
+		}
+
+		var jst []*jobs.State
+		if err := client.Call(informerJobs, plugin, &jst); err != nil {
+			r.JobsError = err.Error()
+		} else {
+			r.Jobs = jst
+			sort.Slice(r.Jobs, func(i, j int) bool {
+				return r.Jobs[i].Pipeline < r.Jobs[j].Pipeline
+			})
+		}
+
+		reports = append(reports, r)
+	}
+
+	return reports
+}
+
+// render writes the reports in the requested format, the errors are written to errW in the table format only.
+func render(w, errW io.Writer, format string, reports []*Report) error {
+	switch format {
+	case outputTable:
+		renderTable(w, errW, reports)
+		return nil
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(reports)
+	case outputYAML:
+		return renderYAML(w, reports)
+	case outputCSV:
+		return renderCSV(w, reports)
+	default:
+		return errors.Errorf("unknown output format %q (allowed: table, json, yaml, csv)", format)
+	}
+}
+
+func renderTable(w, errW io.Writer, reports []*Report) {
+	for _, r := range reports {
+		if r.WorkersError != "" {
+			_, _ = fmt.Fprintln(errW, color.RedString("failed to receive information about %s plugin workers: %s", r.Plugin, r.WorkersError))
+			continue
+		}
+
+		if len(r.Workers) == 0 {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(w, "Workers of [%s]:\n", color.HiYellowString(r.Plugin))
+
+		if r.Plugin == servicePluginName {
+			_ = ServiceWorkerTable(w, r.Workers).Render()
+			continue
+		}
+
+		_ = WorkerTable(w, r.Workers, nil).Render()
+	}
+
+	for _, r := range reports {
+		if r.JobsError != "" {
+			_, _ = fmt.Fprintln(errW, color.RedString("failed to receive information about %s plugin jobs: %s", r.Plugin, r.JobsError))
+			continue
+		}
+
+		// eq to nil
+		if len(r.Jobs) == 0 {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(w, "Jobs of [%s]:\n", color.HiYellowString(r.Plugin))
+		_ = JobsTable(w, r.Jobs, nil).Render()
+	}
+}
+
+// renderYAML uses the same field names as the JSON output.
+func renderYAML(w io.Writer, reports []*Report) error {
+	data, err := json.Marshal(reports)
+	if err != nil {
+		return err
+	}
+
+	var doc any
+	err = json.Unmarshal(data, &doc)
+	if err != nil {
+		return err
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	err = enc.Encode(doc)
+	if err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+// renderCSV writes one row per worker, per pipeline and per failed RPC call, the kind column tells them apart.
+func renderCSV(w io.Writer, reports []*Report) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{
+		"plugin", "kind",
+		"pid", "status", "num_execs", "created", "memory_usage", "cpu_percent", "command", "status_str",
+		"pipeline", "driver", "queue", "active", "delayed", "reserved", "ready", "priority", "error_message",
+		"error",
+	}
+
+	err := cw.Write(header)
+	if err != nil {
+		return err
+	}
+
+	row := func(plugin, kind string) []string {
+		r := make([]string, len(header))
+		r[0], r[1] = plugin, kind
+
+		return r
+	}
+
+	for _, r := range reports {
+		for _, wrk := range r.Workers {
+			rec := row(r.Plugin, "worker")
+			copy(rec[2:], []string{
+				strconv.FormatInt(wrk.Pid, 10),
+				strconv.FormatInt(wrk.Status, 10),
+				strconv.FormatUint(wrk.NumExecs, 10),
+				strconv.FormatInt(wrk.Created, 10),
+				strconv.FormatUint(wrk.MemoryUsage, 10),
+				strconv.FormatFloat(wrk.CPUPercent, 'f', -1, 64),
+				wrk.Command,
+				wrk.StatusStr,
+			})
+
+			if err = cw.Write(rec); err != nil {
+				return err
+			}
+		}
+
+		for _, j := range r.Jobs {
+			rec := row(r.Plugin, "pipeline")
+			copy(rec[10:], []string{
+				j.Pipeline,
+				j.Driver,
+				j.Queue,
+				strconv.FormatInt(j.Active, 10),
+				strconv.FormatInt(j.Delayed, 10),
+				strconv.FormatInt(j.Reserved, 10),
+				strconv.FormatBool(j.Ready),
+				strconv.FormatUint(j.Priority, 10),
+				j.ErrorMessage,
+			})
+
+			if err = cw.Write(rec); err != nil {
+				return err
+			}
+		}
+
+		for _, e := range []struct{ kind, msg string }{{"workers_error", r.WorkersError}, {"jobs_error", r.JobsError}} {
+			if e.msg == "" {
+				continue
+			}
+
+			rec := row(r.Plugin, e.kind)
+			rec[len(rec)-1] = e.msg
+
+			if err = cw.Write(rec); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// colorsDisabled reports whether the output should not be colored: NO_COLOR is set (https://no-color.org) or the
+// output is not a terminal.
+func colorsDisabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return true
+	}
+
+	f, ok := w.(*os.File)
+	if !ok {
+		return true
+	}
+
+	return !isatty.IsTerminal(f.Fd()) && !isatty.IsCygwinTerminal(f.Fd())
+}
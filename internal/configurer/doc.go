@@ -0,0 +1,4 @@
+// Package configurer implements the config plugin the other plugins read their sections from. It serves the
+// configuration resolved by the loader package as is: the ${ENV} references, the includes and the -o overrides are
+// already applied there, so the values (e.g. a password with "$" or an escaped "$$") are never expanded twice.
+package configurer
@@ -0,0 +1,164 @@
+package configurer
+
+import (
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/roadrunner-server/errors"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/loader"
+	"github.com/spf13/viper"
+)
+
+// PluginName is the name of the plugin, the same as of the config plugin it replaces.
+const PluginName string = "config"
+
+// Plugin provides the resolved configuration to the other plugins.
+type Plugin struct {
+	// Config is the resolved configuration, required.
+	Config *loader.Config
+	// Timeout is the graceful timeout of the plugins (endure.grace_period).
+	Timeout time.Duration
+	// Version is the RoadRunner version.
+	Version string
+	// ExperimentalFeatures enables the experimental features of the plugins.
+	ExperimentalFeatures bool
+
+	mu sync.RWMutex
+	v  *viper.Viper
+	// the values set via Overwrite, they are kept on Update
+	overwritten map[string]any
+}
+
+// Init copies the resolved configuration, so Overwrite doesn't change the values the loader returns.
+func (p *Plugin) Init() error {
+	const op = errors.Op("config_plugin_init")
+
+	if p.Config == nil {
+		return errors.E(op, errors.Str("no configuration provided"))
+	}
+
+	v, err := newViper(p.Config, nil)
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	p.mu.Lock()
+	p.v, p.overwritten = v, make(map[string]any)
+	p.mu.Unlock()
+
+	return nil
+}
+
+// Update replaces the configuration of the running plugins, e.g. on reload when the changes are applied without
+// restarting them: the plugins read the new values on the next UnmarshalKey, e.g. when their workers are reset.
+func (p *Plugin) Update(c *loader.Config) error {
+	const op = errors.Op("config_plugin_update")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	v, err := newViper(c, p.overwritten)
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	p.Config, p.v = c, v
+
+	return nil
+}
+
+// Overwrite sets the values, e.g. the plugins defaults.
+func (p *Plugin) Overwrite(values map[string]any) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for k, v := range values {
+		p.v.Set(k, v)
+	}
+
+	maps.Copy(p.overwritten, values)
+
+	return nil
+}
+
+// UnmarshalKey reads the section into the structure.
+func (p *Plugin) UnmarshalKey(name string, out any) error {
+	const op = errors.Op("config_plugin_unmarshal_key")
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	err := p.v.UnmarshalKey(name, out)
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	return nil
+}
+
+// Unmarshal reads the whole configuration into the structure.
+func (p *Plugin) Unmarshal(out any) error {
+	const op = errors.Op("config_plugin_unmarshal")
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	err := p.v.Unmarshal(out)
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	return nil
+}
+
+// Get returns the value by its key in the dot notation.
+func (p *Plugin) Get(name string) any {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.v.Get(name)
+}
+
+// Has checks whether the section or the value is set.
+func (p *Plugin) Has(name string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.v.IsSet(name)
+}
+
+// GracefulTimeout returns the graceful timeout of the plugins.
+func (p *Plugin) GracefulTimeout() time.Duration {
+	return p.Timeout
+}
+
+// RRVersion returns the RoadRunner version.
+func (p *Plugin) RRVersion() string {
+	return p.Version
+}
+
+// Experimental reports whether the experimental features are enabled.
+func (p *Plugin) Experimental() bool {
+	return p.ExperimentalFeatures
+}
+
+// Name returns the plugin name.
+func (p *Plugin) Name() string {
+	return PluginName
+}
+
+func newViper(c *loader.Config, overwritten map[string]any) (*viper.Viper, error) {
+	v := viper.New()
+
+	err := v.MergeConfigMap(c.Settings())
+	if err != nil {
+		return nil, err
+	}
+
+	for k, val := range overwritten {
+		v.Set(k, val)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,86 @@
+package configurer_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/roadrunner-server/roadrunner/v2025/internal/configurer"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/loader"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type redisConfig struct {
+	Password string        `mapstructure:"password"`
+	Secret   string        `mapstructure:"secret"`
+	User     string        `mapstructure:"user"`
+	Addrs    []string      `mapstructure:"addrs"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+}
+
+// the resolved values are not expanded once more
+func TestPlugin_NoSecondExpansion(t *testing.T) {
+	t.Setenv("DOLLAR_SECRET", "s3$HOME${PATH}$")
+	t.Setenv("HOST", "must-not-be-used")
+
+	c, err := loader.Load("test/dollar.yaml", []string{"kv.redis.config.user=$$USER"})
+	require.NoError(t, err)
+
+	p := &configurer.Plugin{Config: c, Timeout: time.Second * 10, Version: "2025.1.0", ExperimentalFeatures: true}
+	require.NoError(t, p.Init())
+
+	cfg := &redisConfig{}
+	require.NoError(t, p.UnmarshalKey("kv.redis.config", cfg))
+	assert.Equal(t, "pa$word", cfg.Password)
+	assert.Equal(t, "s3$HOME${PATH}$", cfg.Secret)
+	assert.Equal(t, "$USER", cfg.User)
+	assert.Equal(t, []string{"${HOST}", "127.0.0.1:6379"}, cfg.Addrs)
+	assert.Equal(t, time.Second*5, cfg.Timeout)
+
+	assert.True(t, p.Has("kv.redis"))
+	assert.False(t, p.Has("http"))
+	assert.Equal(t, "3", p.Get("version"))
+	assert.Equal(t, time.Second*10, p.GracefulTimeout())
+	assert.Equal(t, "2025.1.0", p.RRVersion())
+	assert.True(t, p.Experimental())
+}
+
+// the overwritten values are seen by the plugins only, not by the other consumers of the resolved configuration
+func TestPlugin_Overwrite(t *testing.T) {
+	c, err := loader.Load("test/dollar.yaml", nil)
+	require.NoError(t, err)
+
+	p := &configurer.Plugin{Config: c}
+	require.NoError(t, p.Init())
+	require.NoError(t, p.Overwrite(map[string]any{"kv.redis.config.password": "overwritten"}))
+
+	assert.Equal(t, "overwritten", p.Get("kv.redis.config.password"))
+	assert.Equal(t, "pa$word", c.Viper().GetString("kv.redis.config.password"))
+}
+
+// the reloaded configuration is seen by the running plugins, the overwritten values are kept
+func TestPlugin_Update(t *testing.T) {
+	c, err := loader.Load("test/dollar.yaml", nil)
+	require.NoError(t, err)
+
+	p := &configurer.Plugin{Config: c}
+	require.NoError(t, p.Init())
+	require.NoError(t, p.Overwrite(map[string]any{"http.address": "127.0.0.1:8080"}))
+
+	next, err := loader.Load("test/dollar.yaml", []string{"kv.redis.config.timeout=10s"})
+	require.NoError(t, err)
+	require.NoError(t, p.Update(next))
+
+	cfg := &redisConfig{}
+	require.NoError(t, p.UnmarshalKey("kv.redis.config", cfg))
+	assert.Equal(t, time.Second*10, cfg.Timeout)
+	assert.Equal(t, "pa$word", cfg.Password)
+	assert.Equal(t, "127.0.0.1:8080", p.Get("http.address"))
+	assert.Same(t, next, p.Config)
+}
+
+func TestPlugin_NoConfig(t *testing.T) {
+	err := (&configurer.Plugin{}).Init()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no configuration provided")
+}
@@ -0,0 +1,182 @@
+package debug
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"github.com/roadrunner-server/errors"
+)
+
+const (
+	// DefaultAddress is the loopback address, the debug server is not exposed unless configured.
+	DefaultAddress string = "127.0.0.1:6061"
+	// DefaultProfilesDir is the directory of the continuous profiler, relative to the working directory.
+	DefaultProfilesDir string = "rr-profiles"
+)
+
+// Config is the debug server configuration (endure.debug).
+type Config struct {
+	// Address is a TCP address (127.0.0.1:6061), a DSN (tcp://..., unix:///run/rr-debug.sock) or the socket passed by
+	// systemd (systemd:debug).
+	Address string `mapstructure:"address"`
+	// Username and Password enable the basic authentication.
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// BearerToken enables the `Authorization: Bearer <token>` authentication.
+	BearerToken string `mapstructure:"bearer_token"`
+	// AllowedIPs are the IPs or CIDRs allowed to connect over TCP, all when empty.
+	AllowedIPs []string `mapstructure:"allowed_ips"`
+	// BlockProfileRate is passed to runtime.SetBlockProfileRate, the block profile is empty when zero.
+	BlockProfileRate int `mapstructure:"block_profile_rate"`
+	// MutexProfileFraction is passed to runtime.SetMutexProfileFraction, the mutex profile is empty when zero.
+	MutexProfileFraction int `mapstructure:"mutex_profile_fraction"`
+	// Profiler is the continuous profiler, it runs in the `serve` process without the debug server.
+	Profiler ProfilerConfig `mapstructure:"profiler"`
+}
+
+// ProfilerConfig is the continuous profiler configuration (endure.debug.profiler).
+type ProfilerConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Dir is the directory with the captured profiles.
+	Dir string `mapstructure:"dir"`
+	// MaxSize is the size of the profiles kept in Dir in bytes, the oldest profiles are removed.
+	MaxSize int64 `mapstructure:"max_size"`
+	// Interval between the periodic CPU, heap and goroutine profiles.
+	Interval time.Duration `mapstructure:"interval"`
+	// CPUDuration is how long the CPU profile is collected.
+	CPUDuration time.Duration `mapstructure:"cpu_duration"`
+	// HeapThreshold triggers the snapshot when the heap is above the number of bytes, disabled when zero.
+	HeapThreshold uint64 `mapstructure:"heap_threshold"`
+	// GoroutineThreshold triggers the snapshot when the number of goroutines is above it, disabled when zero.
+	GoroutineThreshold int `mapstructure:"goroutine_threshold"`
+	// CheckInterval is how often the thresholds are checked.
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+	// TriggerCooldown is the minimal interval between the triggered snapshots.
+	TriggerCooldown time.Duration `mapstructure:"trigger_cooldown"`
+}
+
+// InitDefaults sets the defaults of the debug server and the profiler.
+func (c *Config) InitDefaults() {
+	if c.Address == "" {
+		c.Address = DefaultAddress
+	}
+
+	p := &c.Profiler
+	if p.Dir == "" {
+		p.Dir = DefaultProfilesDir
+	}
+
+	if p.MaxSize == 0 {
+		p.MaxSize = 100 * 1024 * 1024
+	}
+
+	if p.Interval == 0 {
+		p.Interval = time.Minute * 10
+	}
+
+	if p.CPUDuration == 0 {
+		p.CPUDuration = time.Second * 10
+	}
+
+	if p.CheckInterval == 0 {
+		p.CheckInterval = time.Second * 10
+	}
+
+	if p.TriggerCooldown == 0 {
+		p.TriggerCooldown = time.Minute * 5
+	}
+}
+
+// Validate checks the authentication settings and the allowlist.
+func (c *Config) Validate() error {
+	if (c.Username == "") != (c.Password == "") {
+		return errors.Str("debug: both username and password should be set for the basic authentication")
+	}
+
+	if c.BlockProfileRate < 0 || c.MutexProfileFraction < 0 {
+		return errors.Str("debug: block_profile_rate and mutex_profile_fraction should not be negative")
+	}
+
+	if _, err := parseAllowlist(c.AllowedIPs); err != nil {
+		return err
+	}
+
+	p := &c.Profiler
+	if !p.Enabled {
+		return nil
+	}
+
+	switch {
+	case p.MaxSize < 0, p.Interval < 0, p.CPUDuration < 0, p.CheckInterval < 0, p.TriggerCooldown < 0, p.GoroutineThreshold < 0:
+		return errors.Str("debug: profiler options should not be negative")
+	case p.CPUDuration >= p.Interval:
+		return errors.Errorf("debug: profiler cpu_duration (%s) should be less than the interval (%s)", p.CPUDuration, p.Interval)
+	}
+
+	return nil
+}
+
+// AuthEnabled reports whether any of the authentication methods is configured.
+func (c *Config) AuthEnabled() bool {
+	return c.Username != "" || c.BearerToken != ""
+}
+
+// parseAllowlist parses the IPs and CIDRs, a single IP is the /32 (/128) network.
+func parseAllowlist(allowed []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(allowed))
+
+	for _, a := range allowed {
+		a = strings.TrimSpace(a)
+
+		if !strings.Contains(a, "/") {
+			ip := net.ParseIP(a)
+			if ip == nil {
+				return nil, errors.Errorf("debug: invalid allowed IP %q", a)
+			}
+
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+
+			continue
+		}
+
+		_, n, err := net.ParseCIDR(a)
+		if err != nil {
+			return nil, errors.Errorf("debug: invalid allowed network %q: %v", a, err)
+		}
+
+		nets = append(nets, n)
+	}
+
+	return nets, nil
+}
+
+// Exposed reports whether the TCP address is reachable from other hosts, e.g. :6061 or 0.0.0.0:6061.
+func Exposed(address string) bool {
+	network, addr := "tcp", address
+	if n, a, ok := strings.Cut(address, "://"); ok {
+		network, addr = n, a
+	}
+
+	if !strings.HasPrefix(network, "tcp") || strings.HasPrefix(address, "systemd:") {
+		return false
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+
+	if host == "localhost" {
+		return false
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip == nil || !ip.IsLoopback()
+}
@@ -1,3 +1,9 @@
-// Package debug provides an HTTP server with pprof endpoints for runtime
-// profiling and diagnostics.
+// Package debug provides an HTTP server with pprof and expvar endpoints for
+// runtime profiling and diagnostics. The server listens on the loopback
+// interface by default and supports the basic and bearer token
+// authentication and an IP allowlist.
+//
+// The continuous profiler periodically captures the CPU, heap and goroutine
+// profiles into a size-bounded directory, and captures an extra snapshot when
+// the heap or the number of goroutines exceeds the configured threshold.
 package debug
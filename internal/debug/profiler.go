@@ -0,0 +1,220 @@
+package debug
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/metrics"
+	"runtime/pprof"
+	"sync"
+	"time"
+)
+
+// the live heap objects, cheaper than runtime.ReadMemStats which stops the world
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// Profiler periodically captures the CPU, heap and goroutine profiles into the directory, and the extra heap and
+// goroutine snapshots when the heap or the number of goroutines is above the threshold. The oldest profiles are
+// removed once the directory is above the max size.
+type Profiler struct {
+	cfg  *ProfilerConfig
+	log  func(string)
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+	// the last triggered snapshot
+	triggered time.Time
+}
+
+// NewProfiler creates the continuous profiler. The configuration should be validated.
+func NewProfiler(cfg *ProfilerConfig, log func(string)) *Profiler {
+	return &Profiler{
+		cfg:  cfg,
+		log:  log,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// Start creates the profiles directory and starts capturing.
+func (p *Profiler) Start() error {
+	if err := os.MkdirAll(p.cfg.Dir, 0o750); err != nil {
+		close(p.done)
+		return err
+	}
+
+	go p.run()
+
+	return nil
+}
+
+// Stop stops capturing and waits for the profile being written, if any. Nil profiler is stopped.
+func (p *Profiler) Stop() {
+	if p == nil {
+		return
+	}
+
+	p.once.Do(func() {
+		close(p.stop)
+	})
+
+	<-p.done
+}
+
+func (p *Profiler) run() {
+	defer close(p.done)
+
+	periodic := time.NewTicker(p.cfg.Interval)
+	defer periodic.Stop()
+
+	var check <-chan time.Time
+	if p.cfg.HeapThreshold > 0 || p.cfg.GoroutineThreshold > 0 {
+		t := time.NewTicker(p.cfg.CheckInterval)
+		defer t.Stop()
+
+		check = t.C
+	}
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-periodic.C:
+			p.capture(ReasonPeriodic, KindCPU, KindHeap, KindGoroutine)
+		case <-check:
+			if reason := p.trigger(); reason != "" {
+				p.capture(reason, KindHeap, KindGoroutine)
+			}
+		}
+	}
+}
+
+// trigger returns the reason of the snapshot, empty when the thresholds are not exceeded or the snapshot was
+// captured recently.
+func (p *Profiler) trigger() string {
+	if !p.triggered.IsZero() && time.Since(p.triggered) < p.cfg.TriggerCooldown {
+		return ""
+	}
+
+	var reason string
+
+	if p.cfg.HeapThreshold > 0 {
+		if heap := heapBytes(); heap > p.cfg.HeapThreshold {
+			p.log(fmt.Sprintf("[WARN] profiler: heap is %d bytes, above %d, capturing the snapshot", heap, p.cfg.HeapThreshold))
+			reason = ReasonHeap
+		}
+	}
+
+	if reason == "" && p.cfg.GoroutineThreshold > 0 {
+		if n := runtime.NumGoroutine(); n > p.cfg.GoroutineThreshold {
+			p.log(fmt.Sprintf("[WARN] profiler: %d goroutines, above %d, capturing the snapshot", n, p.cfg.GoroutineThreshold))
+			reason = ReasonGoroutines
+		}
+	}
+
+	if reason != "" {
+		p.triggered = time.Now()
+	}
+
+	return reason
+}
+
+func (p *Profiler) capture(reason string, kinds ...string) {
+	for _, kind := range kinds {
+		select {
+		case <-p.stop:
+			return
+		default:
+		}
+
+		if err := p.save(kind, reason); err != nil {
+			p.log(fmt.Sprintf("[ERROR] profiler: failed to capture the %s profile: %v", kind, err))
+		}
+	}
+
+	if err := p.rotate(); err != nil {
+		p.log(fmt.Sprintf("[ERROR] profiler: failed to remove the old profiles: %v", err))
+	}
+}
+
+// save writes the profile into the temporary file first, so the incomplete profiles are never listed.
+func (p *Profiler) save(kind, reason string) error {
+	name := profileName(time.Now(), kind, reason)
+
+	f, err := os.CreateTemp(p.cfg.Dir, "."+name+".*")
+	if err != nil {
+		return err
+	}
+
+	err = p.write(f, kind)
+	if errC := f.Close(); err == nil {
+		err = errC
+	}
+
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+
+	return os.Rename(f.Name(), filepath.Join(p.cfg.Dir, name))
+}
+
+func (p *Profiler) write(w io.Writer, kind string) error {
+	if kind != KindCPU {
+		return pprof.Lookup(kind).WriteTo(w, 0)
+	}
+
+	// fails when the CPU profile is collected via the debug server at the same time
+	if err := pprof.StartCPUProfile(w); err != nil {
+		return err
+	}
+
+	t := time.NewTimer(p.cfg.CPUDuration)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+	case <-p.stop:
+	}
+
+	pprof.StopCPUProfile()
+
+	return nil
+}
+
+// rotate removes the oldest profiles while the directory is above the max size, the newest one is always kept.
+func (p *Profiler) rotate() error {
+	profiles, err := ListProfiles(p.cfg.Dir)
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, pr := range profiles {
+		total += pr.Size
+	}
+
+	for len(profiles) > 1 && total > p.cfg.MaxSize {
+		if err = os.Remove(filepath.Join(p.cfg.Dir, profiles[0].Name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		total -= profiles[0].Size
+		profiles = profiles[1:]
+	}
+
+	return nil
+}
+
+func heapBytes() uint64 {
+	sample := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(sample)
+
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+
+	return sample[0].Value.Uint64()
+}
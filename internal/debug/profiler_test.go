@@ -0,0 +1,223 @@
+package debug_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/roadrunner-server/roadrunner/v2025/internal/debug"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func profilerConfig(t *testing.T) *debug.ProfilerConfig {
+	t.Helper()
+
+	cfg := &debug.Config{Profiler: debug.ProfilerConfig{
+		Enabled:     true,
+		Dir:         filepath.Join(t.TempDir(), "profiles"),
+		Interval:    time.Millisecond * 200,
+		CPUDuration: time.Millisecond * 50,
+	}}
+	cfg.InitDefaults()
+	require.NoError(t, cfg.Validate())
+
+	return &cfg.Profiler
+}
+
+func startProfiler(t *testing.T, cfg *debug.ProfilerConfig) *debug.Profiler {
+	t.Helper()
+
+	p := debug.NewProfiler(cfg, func(msg string) { t.Log(msg) })
+	require.NoError(t, p.Start())
+	t.Cleanup(p.Stop)
+
+	return p
+}
+
+func kinds(profiles []*debug.Profile, reason string) map[string]int {
+	found := make(map[string]int)
+	for _, p := range profiles {
+		if p.Reason == reason {
+			found[p.Kind]++
+		}
+	}
+
+	return found
+}
+
+func TestProfilerPeriodic(t *testing.T) {
+	cfg := profilerConfig(t)
+	p := startProfiler(t, cfg)
+
+	require.Eventually(t, func() bool {
+		profiles, err := debug.ListProfiles(cfg.Dir)
+		require.NoError(t, err)
+
+		k := kinds(profiles, debug.ReasonPeriodic)
+
+		return k[debug.KindCPU] > 0 && k[debug.KindHeap] > 0 && k[debug.KindGoroutine] > 0
+	}, time.Second*10, time.Millisecond*50)
+
+	p.Stop()
+
+	profiles, err := debug.ListProfiles(cfg.Dir)
+	require.NoError(t, err)
+
+	for _, pr := range profiles {
+		assert.Positive(t, pr.Size, pr.Name)
+		assert.WithinDuration(t, time.Now(), pr.Captured, time.Minute)
+	}
+
+	// no temporary files are left
+	entries, err := os.ReadDir(cfg.Dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, len(profiles))
+}
+
+func TestProfilerRotation(t *testing.T) {
+	cfg := profilerConfig(t)
+	cfg.MaxSize = 1
+
+	p := startProfiler(t, cfg)
+
+	// the capture is done when the CPU profile is written
+	require.Eventually(t, func() bool {
+		profiles, err := debug.ListProfiles(cfg.Dir)
+		require.NoError(t, err)
+
+		return len(profiles) > 0 && profiles[0].Kind == debug.KindGoroutine
+	}, time.Second*10, time.Millisecond*20)
+
+	p.Stop()
+
+	// only the newest profile is kept
+	profiles, err := debug.ListProfiles(cfg.Dir)
+	require.NoError(t, err)
+	assert.Len(t, profiles, 1)
+}
+
+func TestProfilerTrigger(t *testing.T) {
+	cfg := profilerConfig(t)
+	cfg.Interval = time.Hour
+	cfg.CPUDuration = time.Second
+	cfg.CheckInterval = time.Millisecond * 20
+	cfg.GoroutineThreshold = 1
+
+	p := startProfiler(t, cfg)
+
+	require.Eventually(t, func() bool {
+		profiles, err := debug.ListProfiles(cfg.Dir)
+		require.NoError(t, err)
+
+		k := kinds(profiles, debug.ReasonGoroutines)
+
+		return k[debug.KindHeap] > 0 && k[debug.KindGoroutine] > 0
+	}, time.Second*10, time.Millisecond*20)
+
+	// the cooldown prevents the snapshot storm
+	time.Sleep(time.Millisecond * 200)
+	p.Stop()
+
+	profiles, err := debug.ListProfiles(cfg.Dir)
+	require.NoError(t, err)
+	assert.Len(t, profiles, 2)
+	assert.Empty(t, kinds(profiles, debug.ReasonPeriodic))
+}
+
+func writeProfiles(t *testing.T, names ...string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, name := range names {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name), 0o600))
+	}
+
+	return dir
+}
+
+func TestListProfiles(t *testing.T) {
+	dir := writeProfiles(t,
+		"20261018T030500.000Z_heap_heap.pb.gz",
+		"20261018T030000.000Z_cpu_periodic.pb.gz",
+		".20261018T031000.000Z_cpu_periodic.pb.gz.123",
+		"notes.txt",
+		"invalid_cpu_periodic.pb.gz",
+	)
+
+	profiles, err := debug.ListProfiles(dir)
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+
+	assert.Equal(t, "20261018T030000.000Z_cpu_periodic.pb.gz", profiles[0].Name)
+	assert.Equal(t, debug.KindCPU, profiles[0].Kind)
+	assert.Equal(t, debug.ReasonPeriodic, profiles[0].Reason)
+	assert.Equal(t, time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC), profiles[0].Captured)
+	assert.Equal(t, int64(len(profiles[0].Name)), profiles[0].Size)
+
+	assert.Equal(t, debug.ReasonHeap, profiles[1].Reason)
+}
+
+func readArchive(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	files := make(map[string]string)
+	tr := tar.NewReader(gr)
+
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+
+		files[h.Name] = string(content)
+	}
+
+	return files
+}
+
+func TestExportProfiles(t *testing.T) {
+	dir := writeProfiles(t, "20261018T030000.000Z_cpu_periodic.pb.gz", "20261018T030500.000Z_heap_heap.pb.gz")
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, debug.ExportProfiles(buf, dir, nil))
+	assert.Len(t, readArchive(t, buf.Bytes()), 2)
+
+	buf.Reset()
+	require.NoError(t, debug.ExportProfiles(buf, dir, []string{"20261018T030500.000Z_heap_heap.pb.gz"}))
+	assert.Equal(t, map[string]string{"20261018T030500.000Z_heap_heap.pb.gz": "20261018T030500.000Z_heap_heap.pb.gz"}, readArchive(t, buf.Bytes()))
+
+	err := debug.ExportProfiles(io.Discard, dir, []string{"../secret.pb.gz"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "profile ../secret.pb.gz is not found")
+
+	err = debug.ExportProfiles(io.Discard, t.TempDir(), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no profiles found")
+}
+
+func TestProfilerConfig_Validate(t *testing.T) {
+	cfg := &debug.Config{Profiler: debug.ProfilerConfig{Enabled: true, Interval: time.Second, CPUDuration: time.Minute}}
+	cfg.InitDefaults()
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cpu_duration (1m0s) should be less than the interval (1s)")
+
+	// not validated when disabled
+	cfg.Profiler.Enabled = false
+	require.NoError(t, cfg.Validate())
+}
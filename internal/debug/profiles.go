@@ -0,0 +1,172 @@
+package debug
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/roadrunner-server/errors"
+)
+
+const (
+	// the captured time goes first, so the profiles are sorted by the name
+	profileTimeFormat = "20060102T150405.000Z"
+	profileExt        = ".pb.gz"
+	profileSeparator  = "_"
+)
+
+// Profile kinds.
+const (
+	KindCPU       string = "cpu"
+	KindHeap      string = "heap"
+	KindGoroutine string = "goroutine"
+)
+
+// Profile capture reasons.
+const (
+	ReasonPeriodic   string = "periodic"
+	ReasonHeap       string = "heap"
+	ReasonGoroutines string = "goroutines"
+)
+
+// Profile is the profile captured by the continuous profiler.
+type Profile struct {
+	Name     string
+	Kind     string
+	Reason   string
+	Captured time.Time
+	Size     int64
+}
+
+func profileName(captured time.Time, kind, reason string) string {
+	return captured.UTC().Format(profileTimeFormat) + profileSeparator + kind + profileSeparator + reason + profileExt
+}
+
+// parseProfileName returns false for the files not written by the profiler.
+func parseProfileName(name string) (*Profile, bool) {
+	base, ok := strings.CutSuffix(name, profileExt)
+	if !ok {
+		return nil, false
+	}
+
+	parts := strings.Split(base, profileSeparator)
+	if len(parts) != 3 {
+		return nil, false
+	}
+
+	captured, err := time.Parse(profileTimeFormat, parts[0])
+	if err != nil {
+		return nil, false
+	}
+
+	return &Profile{Name: name, Kind: parts[1], Reason: parts[2], Captured: captured}, true
+}
+
+// ListProfiles returns the profiles in the directory, the oldest first.
+func ListProfiles(dir string) ([]*Profile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []*Profile
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+
+		p, ok := parseProfileName(e.Name())
+		if !ok {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			// removed by the rotation in the meantime
+			continue
+		}
+
+		p.Size = info.Size()
+		profiles = append(profiles, p)
+	}
+
+	slices.SortFunc(profiles, func(a, b *Profile) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return profiles, nil
+}
+
+// ExportProfiles writes the tar.gz archive of the profiles, all of them when no names are passed.
+func ExportProfiles(w io.Writer, dir string, names []string) error {
+	profiles, err := ListProfiles(dir)
+	if err != nil {
+		return err
+	}
+
+	selected := profiles
+	if len(names) > 0 {
+		selected = make([]*Profile, 0, len(names))
+
+		for _, name := range names {
+			i := slices.IndexFunc(profiles, func(p *Profile) bool { return p.Name == name })
+			if i < 0 {
+				return errors.Errorf("profile %s is not found in %s", name, dir)
+			}
+
+			selected = append(selected, profiles[i])
+		}
+	}
+
+	if len(selected) == 0 {
+		return errors.Errorf("no profiles found in %s", dir)
+	}
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	for _, p := range selected {
+		if err = addFile(tw, filepath.Join(dir, p.Name), p); err != nil {
+			return err
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
+
+func addFile(tw *tar.Writer, path string, p *Profile) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	err = tw.WriteHeader(&tar.Header{
+		Name:    p.Name,
+		Mode:    0o600,
+		Size:    info.Size(),
+		ModTime: p.Captured,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tw, f)
+
+	return err
+}
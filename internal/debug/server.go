@@ -2,18 +2,26 @@ package debug
 
 import (
 	"context"
+	"crypto/subtle"
+	"expvar"
+	"net"
 	"net/http"
 	"net/http/pprof"
+	"runtime"
+	"strings"
 	"time"
+
+	"github.com/roadrunner-server/roadrunner/v2025/internal/activation"
 )
 
 // Server is a HTTP server for debugging.
 type Server struct {
 	srv *http.Server
+	cfg *Config
 }
 
-// NewServer creates new HTTP server for debugging.
-func NewServer() Server {
+// NewServer creates new HTTP server for debugging. The configuration should be validated.
+func NewServer(cfg *Config) Server {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/debug/pprof/", pprof.Index)
@@ -22,20 +30,119 @@ func NewServer() Server {
 	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
 	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 
-	return Server{srv: &http.Server{
-		ReadHeaderTimeout: time.Minute * 10,
-		Handler:           mux,
-	}}
+	for _, profile := range []string{"allocs", "heap", "goroutine", "block", "mutex", "threadcreate"} {
+		mux.Handle("/debug/pprof/"+profile, pprof.Handler(profile))
+	}
+
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	// validated by the caller
+	allowed, _ := parseAllowlist(cfg.AllowedIPs)
+
+	return Server{
+		srv: &http.Server{
+			ReadHeaderTimeout: time.Minute * 10,
+			Handler:           guard(cfg, allowed, mux),
+		},
+		cfg: cfg,
+	}
 }
 
-// Start debug server.
+// Start debug server. The address might reference the socket passed by systemd (systemd:NAME), the listener is
+// inherited on the graceful restart.
 func (s *Server) Start(addr string) error {
 	s.srv.Addr = addr
 
-	return s.srv.ListenAndServe()
+	// the profiles are collected only when enabled, both affect the performance
+	if s.cfg.BlockProfileRate > 0 {
+		runtime.SetBlockProfileRate(s.cfg.BlockProfileRate)
+	}
+
+	if s.cfg.MutexProfileFraction > 0 {
+		runtime.SetMutexProfileFraction(s.cfg.MutexProfileFraction)
+	}
+
+	l, err := activation.Listen(addr)
+	if err != nil {
+		return err
+	}
+
+	return s.srv.Serve(l)
 }
 
 // Stop debug server.
 func (s *Server) Stop(ctx context.Context) error {
 	return s.srv.Shutdown(ctx)
 }
+
+// guard checks the client IP and the credentials before the request is passed to the handler.
+func guard(cfg *Config, allowed []*net.IPNet, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !ipAllowed(allowed, r.RemoteAddr) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		if cfg.AuthEnabled() && !authorized(cfg, r) {
+			if cfg.Username != "" {
+				w.Header().Add("WWW-Authenticate", `Basic realm="roadrunner debug"`)
+			}
+
+			if cfg.BearerToken != "" {
+				w.Header().Add("WWW-Authenticate", `Bearer realm="roadrunner debug"`)
+			}
+
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ipAllowed checks the client of the TCP connection, the access to the unix socket is controlled by its permissions.
+func ipAllowed(allowed []*net.IPNet, remoteAddr string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		// unix socket: the remote address is empty or @
+		return remoteAddr == "" || remoteAddr == "@"
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, n := range allowed {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func authorized(cfg *Config, r *http.Request) bool {
+	if cfg.Username != "" {
+		if user, pass, ok := r.BasicAuth(); ok && equal(user, cfg.Username) && equal(pass, cfg.Password) {
+			return true
+		}
+	}
+
+	if cfg.BearerToken != "" {
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && equal(token, cfg.BearerToken) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -2,23 +2,28 @@ package debug_test
 
 import (
 	"context"
+	"io"
 	"math/rand"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/roadrunner-server/roadrunner/v2025/internal/debug"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestServer_StartingAndStopping(t *testing.T) {
 	rand.Seed(time.Now().UnixNano())
 
 	var (
-		s    = debug.NewServer()
+		s    = debug.NewServer(&debug.Config{})
 		port = strconv.Itoa(rand.Intn(10000) + 10000) //nolint:gosec
 	)
 
@@ -55,3 +60,154 @@ func TestServer_StartingAndStopping(t *testing.T) {
 		cancel()
 	}
 }
+
+// startUnix starts the server on the unix socket and returns the client connecting to it.
+func startUnix(t *testing.T, cfg *debug.Config) *http.Client {
+	t.Helper()
+
+	dir, err := os.MkdirTemp("", "dbg")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	sock := filepath.Join(dir, "debug.sock")
+	s := debug.NewServer(cfg)
+
+	go func() { _ = s.Start("unix://" + sock) }()
+	t.Cleanup(func() { _ = s.Stop(context.Background()) })
+
+	require.Eventually(t, func() bool {
+		_, errS := os.Stat(sock)
+		return errS == nil
+	}, time.Second*5, time.Millisecond*10)
+
+	return &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", sock)
+		},
+	}}
+}
+
+func get(t *testing.T, client *http.Client, path string, auth func(r *http.Request)) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://debug"+path, http.NoBody)
+	require.NoError(t, err)
+
+	if auth != nil {
+		auth(req)
+	}
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	return resp
+}
+
+func TestServer_Handlers(t *testing.T) {
+	client := startUnix(t, &debug.Config{})
+
+	for _, path := range []string{
+		"/debug/vars",
+		"/debug/pprof/allocs?debug=1",
+		"/debug/pprof/heap?debug=1",
+		"/debug/pprof/goroutine?debug=1",
+		"/debug/pprof/block?debug=1",
+		"/debug/pprof/mutex?debug=1",
+	} {
+		assert.Equal(t, http.StatusOK, get(t, client, path, nil).StatusCode, path)
+	}
+
+	resp := get(t, client, "/debug/vars", nil)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"memstats"`)
+}
+
+func TestServer_BasicAuth(t *testing.T) {
+	client := startUnix(t, &debug.Config{Username: "admin", Password: "secret"})
+
+	resp := get(t, client, "/debug/pprof/", nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("WWW-Authenticate"), "Basic")
+
+	resp = get(t, client, "/debug/pprof/", func(r *http.Request) { r.SetBasicAuth("admin", "wrong") })
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp = get(t, client, "/debug/pprof/", func(r *http.Request) { r.SetBasicAuth("admin", "secret") })
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestServer_BearerToken(t *testing.T) {
+	client := startUnix(t, &debug.Config{BearerToken: "token"})
+
+	resp := get(t, client, "/debug/vars", func(r *http.Request) { r.Header.Set("Authorization", "Bearer other") })
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("WWW-Authenticate"), "Bearer")
+
+	resp = get(t, client, "/debug/vars", func(r *http.Request) { r.Header.Set("Authorization", "Bearer token") })
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestServer_AllowedIPs(t *testing.T) {
+	tests := []struct {
+		allowed []string
+		status  int
+	}{
+		{allowed: []string{"127.0.0.1"}, status: http.StatusOK},
+		{allowed: []string{"10.0.0.0/8", "127.0.0.0/8"}, status: http.StatusOK},
+		{allowed: []string{"10.0.0.0/8"}, status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.allowed, ","), func(t *testing.T) {
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			port := ln.Addr().(*net.TCPAddr).Port
+			_ = ln.Close()
+
+			s := debug.NewServer(&debug.Config{AllowedIPs: tt.allowed})
+			go func() { _ = s.Start("127.0.0.1:" + strconv.Itoa(port)) }()
+			t.Cleanup(func() { _ = s.Stop(context.Background()) })
+
+			var resp *http.Response
+			require.Eventually(t, func() bool {
+				resp, err = http.Get("http://127.0.0.1:" + strconv.Itoa(port) + "/debug/vars") //nolint:noctx
+				return err == nil
+			}, time.Second*5, time.Millisecond*10)
+
+			_ = resp.Body.Close()
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	require.NoError(t, (&debug.Config{AllowedIPs: []string{"10.0.0.1", "::1", "192.168.0.0/16"}}).Validate())
+
+	err := (&debug.Config{Username: "admin"}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "both username and password")
+
+	err = (&debug.Config{AllowedIPs: []string{"10.0.0"}}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid allowed IP "10.0.0"`)
+
+	err = (&debug.Config{AllowedIPs: []string{"10.0.0.0/33"}}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid allowed network")
+
+	err = (&debug.Config{BlockProfileRate: -1}).Validate()
+	require.Error(t, err)
+}
+
+func TestExposed(t *testing.T) {
+	assert.True(t, debug.Exposed(":6061"))
+	assert.True(t, debug.Exposed("0.0.0.0:6061"))
+	assert.True(t, debug.Exposed("tcp://10.0.0.1:6061"))
+	assert.False(t, debug.Exposed("127.0.0.1:6061"))
+	assert.False(t, debug.Exposed("localhost:6061"))
+	assert.False(t, debug.Exposed("[::1]:6061"))
+	assert.False(t, debug.Exposed("unix:///run/rr-debug.sock"))
+	assert.False(t, debug.Exposed("systemd:debug"))
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package handoff
+
+import (
+	"syscall"
+)
+
+func closeOnExec(fd int) {
+	syscall.CloseOnExec(fd)
+}
@@ -0,0 +1,6 @@
+//go:build windows
+
+package handoff
+
+// closeOnExec does nothing, the descriptors are not passed on Windows.
+func closeOnExec(int) {}
@@ -0,0 +1,7 @@
+// Package handoff implements the overlapping restart and the binary upgrade:
+// the running server starts a new process passing it the listening sockets
+// created via Listen as file descriptors, waits until the new process reports
+// readiness (or its startup error) and only then stops serving. The sockets
+// the plugins bind on their own are not passed, the new process binds them
+// again with SO_REUSEPORT.
+package handoff
@@ -0,0 +1,169 @@
+//go:build !windows
+
+package handoff_test
+
+import (
+	"bufio"
+	"context"
+	stderr "errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/roadrunner-server/roadrunner/v2025/internal/handoff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// the test binary plays the new process
+	childEnv = "RR_HANDOFF_TEST_CHILD"
+	addrEnv  = "RR_HANDOFF_TEST_ADDR"
+)
+
+func TestMain(m *testing.M) {
+	switch os.Getenv(childEnv) {
+	case "ready":
+		os.Exit(child())
+	case "fail":
+		handoff.Setup()
+		handoff.Fail(stderr.New("invalid configuration"))
+		os.Exit(1)
+	case "exit":
+		os.Exit(3)
+	case "watchdog":
+		// the watchdog is enabled for this process only when it's not enabled for another one
+		if _, ok := os.LookupEnv("WATCHDOG_PID"); ok {
+			os.Exit(4)
+		}
+
+		_ = handoff.Ready()
+		os.Exit(0)
+	case "hang":
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// child serves a single connection on the inherited listener, answering with the content of the inherited file.
+func child() int {
+	l, err := handoff.Listen(os.Getenv(addrEnv))
+	if err != nil {
+		return 1
+	}
+
+	f := handoff.Inherited("state")
+	if f == nil {
+		return 1
+	}
+
+	state, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil {
+		return 1
+	}
+
+	if err = handoff.Ready(); err != nil {
+		return 1
+	}
+
+	conn, err := l.Accept()
+	if err != nil {
+		return 1
+	}
+
+	_, _ = conn.Write([]byte("child: " + state))
+	_ = conn.Close()
+
+	return 0
+}
+
+func start(t *testing.T, mode string) *handoff.Process {
+	t.Helper()
+
+	env := append(os.Environ(), childEnv+"="+mode, addrEnv+"=tcp://127.0.0.1:0")
+
+	p, err := handoff.Start(os.Args[0], []string{"-test.run=^$"}, env)
+	require.NoError(t, err)
+
+	return p
+}
+
+func TestHandoff(t *testing.T) {
+	l, err := handoff.Listen("tcp://127.0.0.1:0")
+	require.NoError(t, err)
+
+	state := filepath.Join(t.TempDir(), "state")
+	require.NoError(t, os.WriteFile(state, []byte("inherited\n"), 0o600))
+
+	f, err := os.Open(state)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+
+	handoff.Register("state", f)
+
+	p := start(t, "ready")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	require.NoError(t, p.WaitReady(ctx))
+
+	// the old process stops accepting, the socket stays open in the new one
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "child: inherited\n", line)
+}
+
+func TestHandoffFailed(t *testing.T) {
+	p := start(t, "fail")
+
+	err := p.WaitReady(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to start: invalid configuration")
+}
+
+func TestHandoffExited(t *testing.T) {
+	p := start(t, "exit")
+
+	err := p.WaitReady(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exited before it was ready: exit status 3")
+}
+
+// the watchdog enabled for the current process is passed to the new one, the watchdog of another process is not
+func TestHandoffWatchdog(t *testing.T) {
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()))
+	require.NoError(t, start(t, "watchdog").WaitReady(context.Background()))
+
+	t.Setenv("WATCHDOG_PID", "1")
+	err := start(t, "watchdog").WaitReady(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exited before it was ready: exit status 4")
+}
+
+func TestHandoffTimeout(t *testing.T) {
+	p := start(t, "hang")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*200)
+	defer cancel()
+
+	err := p.WaitReady(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "was not ready: context deadline exceeded")
+
+	// the process is killed
+	assert.Error(t, syscall.Kill(p.Pid(), 0))
+}
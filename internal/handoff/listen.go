@@ -0,0 +1,204 @@
+package handoff
+
+import (
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/roadrunner-server/errors"
+)
+
+const (
+	// EnvReadyFD is the descriptor of the pipe the new process reports its readiness to.
+	EnvReadyFD string = "RR_HANDOFF_READY_FD"
+	// EnvFDs is the `;` separated names of the inherited descriptors, starting from firstFD.
+	EnvFDs string = "RR_HANDOFF_FDS"
+
+	// the ready pipe goes first
+	readyFD = 3
+	firstFD = 4
+	// separator of the inherited descriptors names
+	separator = ";"
+)
+
+// registry of the listeners created by the Listen, they are passed to the new process on restart.
+var (
+	mu        sync.Mutex                      //nolint:gochecknoglobals
+	once      sync.Once                       //nolint:gochecknoglobals
+	inherited map[string]*os.File             //nolint:gochecknoglobals
+	ready     *os.File                        //nolint:gochecknoglobals
+	listeners = make(map[string]net.Listener) //nolint:gochecknoglobals
+	files     = make(map[string]*os.File)     //nolint:gochecknoglobals
+)
+
+// Listen returns the listener inherited from the previous process or creates a new one. The address is either in the
+// DSN form (tcp://127.0.0.1:6001, unix://rr.sock) or a TCP address (:6061). The listener is passed to the new process
+// on restart, so the socket is never closed while the server is being restarted.
+func Listen(address string) (net.Listener, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if f := takeInherited(address); f != nil {
+		l, err := net.FileListener(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, errors.Errorf("inherited listener %s: %v", address, err)
+		}
+
+		listeners[address] = l
+
+		return l, nil
+	}
+
+	network, addr := "tcp", address
+	if n, a, ok := strings.Cut(address, "://"); ok {
+		network, addr = n, a
+	}
+
+	l, err := net.Listen(network, addr) //nolint:noctx
+	if err != nil {
+		return nil, err
+	}
+
+	listeners[address] = l
+
+	return l, nil
+}
+
+// Register adds the file (e.g. the locked pid file) passed to the new process on restart.
+func Register(name string, f *os.File) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	files[name] = f
+}
+
+// Inherited returns the file passed by the previous process, nil when there is no such file. The file is returned
+// only once.
+func Inherited(name string) *os.File {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return takeInherited(name)
+}
+
+// InheritedNames returns the sorted names of the files passed by the previous process and not taken yet.
+func InheritedNames() []string {
+	mu.Lock()
+	defer mu.Unlock()
+
+	once.Do(parseInherited)
+
+	names := make([]string, 0, len(inherited))
+	for name := range inherited {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// Setup takes over the descriptors passed by the previous process. It should be called before any worker is started,
+// so the descriptors don't leak into the workers, the other functions call it implicitly.
+func Setup() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	once.Do(parseInherited)
+}
+
+func takeInherited(name string) *os.File {
+	once.Do(parseInherited)
+
+	f := inherited[name]
+	delete(inherited, name)
+
+	return f
+}
+
+// parseInherited reads the descriptors passed by the previous process, the variables are unset so they aren't leaked
+// into the workers.
+func parseInherited() {
+	inherited = make(map[string]*os.File)
+
+	// the descriptors should not leak into the workers
+	v, ok := os.LookupEnv(EnvReadyFD)
+	_ = os.Unsetenv(EnvReadyFD)
+
+	if fd, err := strconv.Atoi(v); ok && err == nil {
+		closeOnExec(fd)
+		ready = os.NewFile(uintptr(fd), "handoff-ready")
+	}
+
+	names := os.Getenv(EnvFDs)
+	_ = os.Unsetenv(EnvFDs)
+
+	if names == "" {
+		return
+	}
+
+	for i, name := range strings.Split(names, separator) {
+		fd := firstFD + i
+		closeOnExec(fd)
+		inherited[name] = os.NewFile(uintptr(fd), name+":"+strconv.Itoa(fd))
+	}
+}
+
+// exported returns the names and the descriptors of the registered listeners and files. The listeners descriptors are
+// duplicated, the caller closes them once the new process is started.
+func exported() ([]string, []*os.File, []*os.File, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var (
+		names []string
+		fds   []*os.File
+		dups  []*os.File
+	)
+
+	for address, l := range listeners {
+		fl, ok := l.(interface{ File() (*os.File, error) })
+		if !ok {
+			continue
+		}
+
+		f, err := fl.File()
+		if err != nil {
+			// closed listener, e.g. the server was stopped
+			continue
+		}
+
+		// the socket is used by the new process now, the old one should not remove it on close
+		if ul, ok := l.(*net.UnixListener); ok {
+			ul.SetUnlinkOnClose(false)
+		}
+
+		names = append(names, address)
+		fds = append(fds, f)
+		dups = append(dups, f)
+	}
+
+	for name, f := range files {
+		names = append(names, name)
+		fds = append(fds, f)
+	}
+
+	for _, name := range names {
+		if strings.Contains(name, separator) {
+			closeAll(dups)
+			return nil, nil, nil, errors.Errorf("descriptor name %q should not contain %q", name, separator)
+		}
+	}
+
+	return names, fds, dups, nil
+}
+
+func closeAll(files []*os.File) {
+	for _, f := range files {
+		_ = f.Close()
+	}
+}
@@ -0,0 +1,167 @@
+package handoff
+
+import (
+	"context"
+	stderr "errors"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/roadrunner-server/errors"
+)
+
+const (
+	// readyMessage is written by the new process into the ready pipe.
+	readyMessage = "ready\n"
+	// envWatchdogPID is the process the service manager watchdog is enabled for.
+	envWatchdogPID = "WATCHDOG_PID"
+	// failedPrefix precedes the error of the new process which failed to start.
+	failedPrefix = "error\n"
+)
+
+// Process is the new server process started by the restart.
+type Process struct {
+	cmd   *exec.Cmd
+	ready *os.File
+	// closed when the process exits
+	done chan struct{}
+	err  error
+}
+
+// Start starts the binary with the listeners and the files registered in this process. The process keeps the standard
+// streams of the current one.
+func Start(executable string, args, env []string) (*Process, error) {
+	const op = errors.Op("handoff_start")
+
+	names, fds, dups, err := exported()
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	defer closeAll(dups)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	cmd := exec.Command(executable, args...) //nolint:gosec
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = append([]*os.File{w}, fds...)
+	cmd.Env = append(withoutHandoff(env),
+		EnvReadyFD+"="+strconv.Itoa(readyFD),
+		EnvFDs+"="+strings.Join(names, separator),
+	)
+
+	err = cmd.Start()
+	// the write end belongs to the new process now, EOF is read when it exits
+	_ = w.Close()
+	if err != nil {
+		_ = r.Close()
+		return nil, errors.E(op, err)
+	}
+
+	p := &Process{cmd: cmd, ready: r, done: make(chan struct{})}
+
+	go func() {
+		p.err = cmd.Wait()
+		close(p.done)
+	}()
+
+	return p, nil
+}
+
+// Pid returns the PID of the new process.
+func (p *Process) Pid() int {
+	return p.cmd.Process.Pid
+}
+
+// WaitReady waits until the new process reports its readiness. The process which failed to start reports the error
+// before it exits, the process which exited without a report is described by its exit status.
+func (p *Process) WaitReady(ctx context.Context) error {
+	res := make(chan error, 1)
+
+	go func() {
+		data, err := io.ReadAll(p.ready)
+		_ = p.ready.Close()
+
+		msg := string(data)
+
+		switch {
+		case msg == readyMessage:
+			res <- nil
+		case strings.HasPrefix(msg, failedPrefix):
+			<-p.done
+			res <- errors.Errorf("new process %d failed to start: %s", p.Pid(), strings.TrimPrefix(msg, failedPrefix))
+		case err != nil && !stderr.Is(err, os.ErrClosed):
+			res <- err
+		default:
+			// the pipe is closed when the process exits, wait for its status
+			<-p.done
+			res <- errors.Errorf("new process %d exited before it was ready: %v", p.Pid(), p.err)
+		}
+	}()
+
+	select {
+	case err := <-res:
+		return err
+	case <-ctx.Done():
+		_ = p.ready.Close()
+		_ = p.cmd.Process.Kill()
+		<-p.done
+
+		return errors.Errorf("new process %d was not ready: %v", p.Pid(), ctx.Err())
+	}
+}
+
+// Ready reports the readiness to the previous process, does nothing when the process is not started by the restart.
+func Ready() error {
+	return report(readyMessage)
+}
+
+// Fail reports the startup error to the previous process, which keeps serving. Does nothing when the process is not
+// started by the restart or the readiness is already reported.
+func Fail(err error) {
+	_ = report(failedPrefix + err.Error())
+}
+
+func report(msg string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	once.Do(parseInherited)
+
+	if ready == nil {
+		return nil
+	}
+
+	defer func() {
+		_ = ready.Close()
+		ready = nil
+	}()
+
+	_, err := ready.WriteString(msg)
+
+	return err
+}
+
+// withoutHandoff removes the variables of the previous restart. The watchdog enabled for the current process
+// (WATCHDOG_PID) is passed to the new one, which becomes the main process of the service.
+func withoutHandoff(env []string) []string {
+	watchdog := envWatchdogPID + "=" + strconv.Itoa(os.Getpid())
+
+	res := make([]string, 0, len(env))
+	for _, kv := range env {
+		if strings.HasPrefix(kv, EnvReadyFD+"=") || strings.HasPrefix(kv, EnvFDs+"=") || kv == watchdog {
+			continue
+		}
+
+		res = append(res, kv)
+	}
+
+	return res
+}
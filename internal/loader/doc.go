@@ -0,0 +1,5 @@
+// Package loader resolves the RoadRunner configuration file. It reads the root file, expands the ${ENV} references,
+// merges the included files and applies the -o overrides, keeping track of where every value came from. The result is
+// shared by the serve command, the CLI commands talking to the RPC, the endure container configuration and lib.NewRR,
+// so all of them see exactly the same values.
+package loader
@@ -0,0 +1,278 @@
+package loader
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Expand expands the shell parameter references in the string, the variables are resolved by the lookup function
+// (e.g. os.LookupEnv):
+//
+//	$NAME, ${NAME}    the value of the variable, empty string if it's not set
+//	${NAME:-word}     word if NAME is unset or empty
+//	${NAME-word}      word if NAME is unset
+//	${NAME:=word}     the same as ${NAME:-word}, the environment is not modified
+//	${NAME=word}      the same as ${NAME-word}, the environment is not modified
+//	${NAME:?message}  fails with the message if NAME is unset or empty
+//	${NAME?message}   fails with the message if NAME is unset
+//	${NAME:+word}     word if NAME is set and not empty, empty string otherwise
+//	${NAME+word}      word if NAME is set, empty string otherwise
+//	${file:path}      the content of the file, e.g. a Docker or Kubernetes secret
+//	${secretdir:name} the content of the file in the secrets directory (RR_SECRETS_DIR, /run/secrets by default)
+//	$$                a literal $
+//
+// The trailing newlines are trimmed from the secret files, the files writable by others are rejected.
+// The word is expanded as well, so the defaults can be nested: ${A:-${B:-x}}. It's expanded only when used.
+func Expand(s string, lookup func(string) (string, bool)) (string, error) {
+	e := &expander{lookup: lookup}
+	return e.expand(s)
+}
+
+// expander expands a single value and keeps track of the referenced variables.
+type expander struct {
+	lookup func(string) (string, bool)
+	// names of the referenced variables
+	vars []string
+	// the default value was used instead of the variable
+	defaulted bool
+	// paths and values of the referenced secrets
+	secrets []string
+	values  []string
+}
+
+func (e *expander) expand(s string) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+
+		switch next := s[i+1]; {
+		case next == '$':
+			// $$ -> $
+			sb.WriteByte('$')
+			i++
+		case next == '{':
+			end, err := closingBrace(s, i+2)
+			if err != nil {
+				return "", err
+			}
+
+			val, err := e.param(s[i+2 : end])
+			if err != nil {
+				return "", err
+			}
+
+			sb.WriteString(val)
+			i = end
+		case isNameStart(next):
+			j := i + 1
+			for j < len(s) && isAlphaNum(s[j]) {
+				j++
+			}
+
+			val, _ := e.get(s[i+1 : j])
+			sb.WriteString(val)
+			i = j - 1
+		default:
+			// $ not followed by a name, e.g. "100$", leave it untouched
+			sb.WriteByte('$')
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// param expands a single ${...} reference, the expr is the content between the braces.
+func (e *expander) param(expr string) (string, error) {
+	if kind, ref, ok := secretRef(expr); ok {
+		return e.secret(kind, ref)
+	}
+
+	n := 0
+	for n < len(expr) && isAlphaNum(expr[n]) {
+		n++
+	}
+
+	name, rest := expr[:n], expr[n:]
+	if name == "" || !isNameStart(name[0]) {
+		return "", fmt.Errorf("bad substitution: ${%s}", expr)
+	}
+
+	val, set := e.get(name)
+	if rest == "" {
+		return val, nil
+	}
+
+	// with the colon the empty variable is treated as unset
+	colon := rest[0] == ':'
+	if colon {
+		rest = rest[1:]
+	}
+
+	if rest == "" {
+		return "", fmt.Errorf("bad substitution: ${%s}", expr)
+	}
+
+	present := set && (!colon || val != "")
+	op, word := rest[0], rest[1:]
+
+	switch op {
+	case '-', '=':
+		if present {
+			return val, nil
+		}
+
+		e.defaulted = true
+		return e.expand(word)
+	case '?':
+		if present {
+			return val, nil
+		}
+
+		msg, err := e.expand(word)
+		if err != nil {
+			return "", err
+		}
+
+		if msg == "" {
+			msg = "parameter not set"
+			if colon {
+				msg = "parameter null or not set"
+			}
+		}
+
+		return "", fmt.Errorf("%s: %s", name, msg)
+	case '+':
+		if present {
+			return e.expand(word)
+		}
+
+		return "", nil
+	default:
+		return "", fmt.Errorf("bad substitution: ${%s}", expr)
+	}
+}
+
+// secret reads the secret referenced by ${file:path} or ${secretdir:name}, the path may contain references as well.
+func (e *expander) secret(kind, ref string) (string, error) {
+	ref, err := e.expand(ref)
+	if err != nil {
+		return "", err
+	}
+
+	path, err := secretPath(kind, ref)
+	if err != nil {
+		return "", err
+	}
+
+	val, err := readSecret(path)
+	if err != nil {
+		return "", err
+	}
+
+	e.secrets = append(e.secrets, path)
+	e.values = append(e.values, val)
+
+	return val, nil
+}
+
+func (e *expander) get(name string) (string, bool) {
+	e.vars = append(e.vars, name)
+	return e.lookup(name)
+}
+
+// closingBrace returns the position of the brace closing the reference which content starts at the start position.
+func closingBrace(s string, start int) (int, error) {
+	depth := 1
+	for i := start; i < len(s); i++ {
+		switch {
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '$':
+			i++
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '{':
+			depth++
+			i++
+		case s[i] == '}':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("unterminated parameter expansion: %s", s[start-2:])
+}
+
+// expandEnv expands ${ENV} and secret references in the string values of the scalars, lists and maps, records the
+// origins of the expanded values and collects the resolved secrets.
+func expandEnv(val any, key, file string, origins map[string]Origin, secrets *[]string) (any, error) {
+	switch t := val.(type) {
+	case string:
+		e := &expander{lookup: os.LookupEnv}
+		res, err := e.expand(t)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", file, key, err)
+		}
+
+		if o, ok := e.origin(file); ok {
+			origins[key] = o
+		}
+
+		*secrets = append(*secrets, e.values...)
+
+		return res, nil
+	case map[string]any:
+		for k, v := range t {
+			res, err := expandEnv(v, joinKey(key, k), file, origins, secrets)
+			if err != nil {
+				return nil, err
+			}
+
+			t[k] = res
+		}
+
+		return t, nil
+	case []any:
+		for i := range t {
+			res, err := expandEnv(t[i], key, file, origins, secrets)
+			if err != nil {
+				return nil, err
+			}
+
+			t[i] = res
+		}
+
+		return t, nil
+	default:
+		return val, nil
+	}
+}
+
+// origin returns the origin of the expanded value, if it had any references.
+func (e *expander) origin(file string) (Origin, bool) {
+	switch {
+	case len(e.secrets) > 0:
+		return Origin{Kind: OriginSecret, File: file, Env: e.vars, Secrets: e.secrets}, true
+	case e.defaulted:
+		return Origin{Kind: OriginDefault, File: file, Env: e.vars}, true
+	case len(e.vars) > 0:
+		return Origin{Kind: OriginEnv, File: file, Env: e.vars}, true
+	default:
+		return Origin{}, false
+	}
+}
+
+func isNameStart(c uint8) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// isAlphaNum reports whether the byte is an ASCII letter, number, or underscore.
+func isAlphaNum(c uint8) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
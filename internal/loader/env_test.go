@@ -0,0 +1,129 @@
+package loader_test
+
+import (
+	"testing"
+
+	"github.com/roadrunner-server/roadrunner/v2025/internal/loader"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpand(t *testing.T) {
+	env := map[string]string{
+		"HOST":  "127.0.0.1",
+		"PORT":  "8080",
+		"EMPTY": "",
+	}
+
+	lookup := func(name string) (string, bool) {
+		val, ok := env[name]
+		return val, ok
+	}
+
+	for _, tt := range []struct {
+		give string
+		want string
+	}{
+		{give: "no references", want: "no references"},
+		{give: "$HOST:$PORT", want: "127.0.0.1:8080"},
+		{give: "tcp://${HOST}:${PORT}", want: "tcp://127.0.0.1:8080"},
+		{give: "${UNSET}", want: ""},
+		// mixed strings: the default applies to its own reference only
+		{give: "${HOST}:${UNSET:-9000}", want: "127.0.0.1:9000"},
+		{give: "${UNSET:-a}/${PORT}", want: "a/8080"},
+		// :- vs -
+		{give: "${EMPTY:-default}", want: "default"},
+		{give: "${EMPTY-default}", want: ""},
+		{give: "${UNSET-default}", want: "default"},
+		{give: "${UNSET:=default}", want: "default"},
+		{give: "${EMPTY=default}", want: ""},
+		// :+ vs +
+		{give: "${PORT:+alt}", want: "alt"},
+		{give: "${EMPTY:+alt}", want: ""},
+		{give: "${EMPTY+alt}", want: "alt"},
+		{give: "${UNSET+alt}", want: ""},
+		// nested
+		{give: "${UNSET:-${PORT}}", want: "8080"},
+		{give: "${UNSET:-${NOPE:-x}}", want: "x"},
+		{give: "${PORT:-${NOPE:?never evaluated}}", want: "8080"},
+		// escaping
+		{give: "pa$$word", want: "pa$word"},
+		{give: "$${HOST}", want: "${HOST}"},
+		{give: "${UNSET:-$$}", want: "$"},
+		// $ not followed by a name
+		{give: "100$", want: "100$"},
+		{give: "$1", want: "$1"},
+		{give: "a $ b", want: "a $ b"},
+	} {
+		t.Run(tt.give, func(t *testing.T) {
+			got, err := loader.Expand(tt.give, lookup)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExpand_Errors(t *testing.T) {
+	lookup := func(name string) (string, bool) {
+		if name == "EMPTY" {
+			return "", true
+		}
+
+		return "", false
+	}
+
+	for _, tt := range []struct {
+		give string
+		want string
+	}{
+		{give: "${DSN:?database DSN is required}", want: "DSN: database DSN is required"},
+		{give: "${EMPTY:?}", want: "EMPTY: parameter null or not set"},
+		{give: "${DSN?}", want: "DSN: parameter not set"},
+		{give: "${UNSET:-${DSN:?nested}}", want: "DSN: nested"},
+		{give: "${HOST", want: "unterminated parameter expansion: ${HOST"},
+		{give: "${}", want: "bad substitution: ${}"},
+		{give: "${1abc}", want: "bad substitution: ${1abc}"},
+		{give: "${HOST:}", want: "bad substitution: ${HOST:}"},
+		{give: "${HOST%x}", want: "bad substitution: ${HOST%x}"},
+	} {
+		t.Run(tt.give, func(t *testing.T) {
+			_, err := loader.Expand(tt.give, lookup)
+			require.Error(t, err)
+			assert.Equal(t, tt.want, err.Error())
+		})
+	}
+
+	// no error when the variable is empty and no colon is used
+	got, err := loader.Expand("${EMPTY?unused}", lookup)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestLoad_ExpandNested(t *testing.T) {
+	t.Setenv("RR_TEST_HOST", "127.0.0.1")
+	t.Setenv("RR_TEST_TOKEN", "secret")
+
+	c, err := loader.Load("test/env.yaml", []string{"server.env.MODE=${RR_TEST_MODE:-prod}"})
+	require.NoError(t, err)
+
+	v := c.Viper()
+	assert.Equal(t, "127.0.0.1:8080", v.GetString("http.address"))
+	assert.Equal(t, []string{"127.0.0.1", "fallback", "$literal"}, v.GetStringSlice("http.trusted_subnets"))
+	assert.Equal(t, "Bearer secret", v.GetString("http.pool.supervisor.headers.authorization"))
+	assert.Equal(t, "prod", v.GetString("server.env.mode"))
+
+	cmd, ok := c.Settings()["server"].(map[string]any)["command"].([]any)
+	require.True(t, ok)
+	assert.Equal(t, []any{"php", "worker.php", map[string]any{"host": "127.0.0.1"}}, cmd)
+
+	o, ok := c.Origin("http.address")
+	require.True(t, ok)
+	assert.Equal(t, loader.OriginDefault, o.Kind)
+	assert.Equal(t, []string{"RR_TEST_HOST", "RR_TEST_PORT"}, o.Env)
+}
+
+func TestLoad_ExpandRequired(t *testing.T) {
+	_, err := loader.Load("test/env-required.yaml", nil)
+	require.Error(t, err)
+	assert.Equal(t, "test/env-required.yaml: kv.redis.config.addrs: RR_TEST_REDIS: redis address is required", err.Error())
+}
@@ -0,0 +1,57 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+func parseFlag(flag string) (string, string, error) {
+	if !strings.Contains(flag, "=") {
+		return "", "", fmt.Errorf("invalid flag `%s`", flag)
+	}
+
+	parts := strings.SplitN(strings.TrimLeft(flag, " \"'`"), "=", 2)
+	if len(parts) < 2 {
+		return "", "", errors.New("usage: -o key=value")
+	}
+
+	if parts[0] == "" {
+		return "", "", errors.New("key should not be empty")
+	}
+
+	if parts[1] == "" {
+		return "", "", errors.New("value should not be empty")
+	}
+
+	return strings.Trim(parts[0], " \n\t"), parseValue(strings.Trim(parts[1], " \n\t")), nil
+}
+
+func parseValue(value string) string {
+	escape := []rune(value)[0]
+
+	if escape == '"' || escape == '\'' || escape == '`' {
+		value = strings.Trim(value, string(escape))
+		value = strings.ReplaceAll(value, fmt.Sprintf("\\%s", string(escape)), string(escape))
+	}
+
+	return value
+}
+
+// setKey sets the value by its key in the dot notation, creating the missing sections.
+func setKey(settings map[string]any, key string, val any) {
+	parts := strings.Split(strings.ToLower(key), ".")
+
+	section := settings
+	for _, p := range parts[:len(parts)-1] {
+		next, ok := section[p].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			section[p] = next
+		}
+
+		section = next
+	}
+
+	section[parts[len(parts)-1]] = val
+}
@@ -0,0 +1,258 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/roadrunner-server/errors"
+)
+
+const (
+	versionKey      string = "version"
+	includeKey      string = "include"
+	includeListsKey string = "include_lists"
+)
+
+// merge strategies for the lists defined in both the including and the included file
+const (
+	// listsReplace - the list from the included file replaces the previous one (default)
+	listsReplace string = "replace"
+	// listsAppend - the list from the included file is appended to the previous one
+	listsAppend string = "append"
+)
+
+// configFile is a single configuration file with the ${ENV} references expanded.
+type configFile struct {
+	settings map[string]any
+	origins  map[string]Origin
+	version  string
+	includes []string
+	secrets  []string
+}
+
+func getConfiguration(path string) (*configFile, error) {
+	settings, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// get configuration version
+	ver := settings[versionKey]
+	if ver == nil {
+		return nil, errors.Str("rr configuration file should contain a version e.g: version: 3")
+	}
+
+	if _, ok := ver.(string); !ok {
+		return nil, errors.Errorf("type of version should be string, actual: %T", ver)
+	}
+
+	origins := make(map[string]Origin)
+	fileOrigins(settings, "", Origin{Kind: OriginInclude, File: path}, origins)
+
+	var secrets []string
+
+	// automatically inject ENV variables using ${ENV} pattern
+	_, err = expandEnv(settings, "", path, origins, &secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	includes := stringList(settings[includeKey])
+
+	// nested includes are resolved by the includer, the lists strategy is taken from the root file only
+	delete(settings, includeKey)
+	delete(settings, includeListsKey)
+	dropOrigins(origins, includeKey)
+	dropOrigins(origins, includeListsKey)
+
+	return &configFile{
+		settings: settings,
+		origins:  origins,
+		version:  ver.(string),
+		includes: includes,
+		secrets:  secrets,
+	}, nil
+}
+
+// includer merges the included files into the root configuration. Included files may include other files, paths
+// are resolved relative to the including file and may contain glob patterns (e.g. conf.d/*.yaml).
+type includer struct {
+	rootVersion string
+	lists       string
+	// absolute paths of the files being loaded, used to detect cycles
+	stack []string
+	// loaded files in the order of precedence
+	files []string
+	// resolved secrets of all the loaded files
+	secrets *[]string
+}
+
+func handleInclude(rootVersion string, root string, settings map[string]any, origins map[string]Origin, secrets *[]string, includes []string, lists string) ([]string, error) {
+	switch lists {
+	case "":
+		lists = listsReplace
+	case listsReplace, listsAppend:
+	default:
+		return nil, errors.Errorf("unknown %s strategy: %s (allowed: %s, %s)", includeListsKey, lists, listsReplace, listsAppend)
+	}
+
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	in := &includer{
+		rootVersion: rootVersion,
+		lists:       lists,
+		stack:       []string{abs},
+		files:       []string{root},
+		secrets:     secrets,
+	}
+
+	err = in.include(root, includes, settings, origins)
+	if err != nil {
+		return nil, err
+	}
+
+	return in.files, nil
+}
+
+func (in *includer) include(from string, patterns []string, settings map[string]any, origins map[string]Origin) error {
+	for _, pattern := range patterns {
+		files, err := resolveInclude(from, pattern)
+		if err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			err = in.load(file, settings, origins)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (in *includer) load(file string, settings map[string]any, origins map[string]Origin) error {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(in.stack, abs) {
+		return errors.Errorf("include cycle detected: %s", strings.Join(append(in.stack, abs), " -> "))
+	}
+
+	in.stack = append(in.stack, abs)
+	defer func() {
+		in.stack = in.stack[:len(in.stack)-1]
+	}()
+
+	cfg, err := getConfiguration(file)
+	if err != nil {
+		return err
+	}
+
+	if cfg.version != in.rootVersion {
+		return errors.Errorf("version in included file must be the same as in root: %s", file)
+	}
+
+	in.files = append(in.files, file)
+	*in.secrets = append(*in.secrets, cfg.secrets...)
+	mergeMaps(settings, cfg.settings, "", in.lists, origins, cfg.origins)
+
+	// files included by this file override it
+	return in.include(file, cfg.includes, settings, origins)
+}
+
+// resolveInclude returns the files matching the include pattern. Relative patterns are resolved against the
+// directory of the including file, falling back to the working directory.
+func resolveInclude(from, pattern string) ([]string, error) {
+	candidates := []string{pattern}
+	if !filepath.IsAbs(pattern) {
+		candidates = []string{filepath.Join(filepath.Dir(from), pattern), pattern}
+	}
+
+	for _, c := range candidates {
+		matches, err := filepath.Glob(c)
+		if err != nil {
+			return nil, errors.Errorf("invalid include pattern %s: %v", pattern, err)
+		}
+
+		if len(matches) > 0 {
+			return matches, nil
+		}
+	}
+
+	// a pattern without matches is fine (e.g. empty conf.d), a missing file is not
+	if hasMeta(pattern) {
+		return nil, nil
+	}
+
+	return nil, errors.Errorf("included file not found: %s: %v", pattern, os.ErrNotExist)
+}
+
+func hasMeta(path string) bool {
+	return strings.ContainsAny(path, `*?[\`)
+}
+
+// mergeMaps deep merges src into dst: nested maps are merged, lists are replaced or appended depending on the
+// strategy and other values are replaced. The origins of the replaced values are updated accordingly.
+func mergeMaps(dst, src map[string]any, prefix, lists string, origins, srcOrigins map[string]Origin) {
+	for k, sv := range src {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		dv, exists := dst[k]
+
+		dm, dstIsMap := dv.(map[string]any)
+		sm, srcIsMap := sv.(map[string]any)
+		if dstIsMap && srcIsMap {
+			mergeMaps(dm, sm, key, lists, origins, srcOrigins)
+			continue
+		}
+
+		if lists == listsAppend && exists {
+			dl, dstIsList := toList(dv)
+			sl, srcIsList := toList(sv)
+			if dstIsList && srcIsList {
+				dst[k] = append(slices.Clone(dl), sl...)
+				if o, ok := srcOrigins[key]; ok {
+					origins[key] = o
+				}
+
+				continue
+			}
+		}
+
+		dst[k] = sv
+		dropOrigins(origins, key)
+		for sk, o := range srcOrigins {
+			if sk == key || strings.HasPrefix(sk, key+".") {
+				origins[sk] = o
+			}
+		}
+	}
+}
+
+func toList(val any) ([]any, bool) {
+	switch t := val.(type) {
+	case []any:
+		return t, true
+	case []string:
+		res := make([]any, len(t))
+		for i := range t {
+			res[i] = t[i]
+		}
+
+		return res, true
+	default:
+		return nil, false
+	}
+}
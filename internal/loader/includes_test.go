@@ -0,0 +1,74 @@
+package loader_test
+
+import (
+	"testing"
+
+	"github.com/roadrunner-server/roadrunner/v2025/internal/loader"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_IncludeDeepMerge(t *testing.T) {
+	c, err := loader.Load("test/merge/.rr.yaml", nil)
+	require.NoError(t, err)
+
+	v := c.Viper()
+	// values from the root file survive the merge
+	assert.Equal(t, "127.0.0.1:8080", v.GetString("http.address"))
+	assert.False(t, v.GetBool("http.pool.debug"))
+	assert.Equal(t, "php worker.php", v.GetString("server.command"))
+	// included values override the root ones
+	assert.Equal(t, 4, v.GetInt("http.pool.num_workers"))
+	// lists are replaced by default
+	assert.Equal(t, []string{"headers"}, v.GetStringSlice("http.middleware"))
+	// glob include
+	assert.Equal(t, "debug", v.GetString("logs.level"))
+	// recursive includes, resolved relative to the including file
+	assert.Equal(t, "tcp://127.0.0.1:6002", v.GetString("rpc.listen"))
+	assert.Equal(t, "production", v.GetString("logs.mode"))
+
+	assert.Equal(t, []string{
+		"test/merge/.rr.yaml",
+		"test/merge/conf.d/10-http.yaml",
+		"test/merge/conf.d/20-logs.yaml",
+		"test/merge/extra.yaml",
+		"test/merge/nested/nested.yaml",
+		"test/merge/nested/sibling.yaml",
+	}, c.Files())
+
+	o, ok := c.Origin("http.pool.num_workers")
+	require.True(t, ok)
+	assert.Equal(t, loader.Origin{Kind: loader.OriginInclude, File: "test/merge/conf.d/10-http.yaml"}, o)
+
+	o, ok = c.Origin("http.address")
+	require.True(t, ok)
+	assert.Equal(t, loader.Origin{Kind: loader.OriginFile, File: "test/merge/.rr.yaml"}, o)
+}
+
+func TestLoad_IncludeAppendLists(t *testing.T) {
+	c, err := loader.Load("test/merge/.rr-append.yaml", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"gzip", "headers"}, c.Viper().GetStringSlice("http.middleware"))
+}
+
+func TestLoad_OverrideWinsOverInclude(t *testing.T) {
+	c, err := loader.Load("test/merge/.rr.yaml", []string{"http.pool.num_workers=8"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 8, c.Viper().GetInt("http.pool.num_workers"))
+	assert.Equal(t, "127.0.0.1:8080", c.Viper().GetString("http.address"))
+}
+
+func TestLoad_IncludeCycle(t *testing.T) {
+	_, err := loader.Load("test/cycle/a.yaml", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "include cycle detected")
+	assert.Contains(t, err.Error(), "a.yaml -> ")
+}
+
+func TestLoad_IncludeMissing(t *testing.T) {
+	_, err := loader.Load("test/merge/.rr-missing.yaml", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "included file not found: missing.yaml")
+}
@@ -0,0 +1,211 @@
+package loader
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+)
+
+// Config is the resolved RR configuration with the origin of every value.
+type Config struct {
+	settings map[string]any
+	v        *viper.Viper
+	origins  map[string]Origin
+	files    []string
+	// resolved secret values
+	secrets []string
+}
+
+// Load reads the configuration file: it expands ${ENV} references, merges the included files and applies the -o
+// overrides. The configuration is resolved once, every consumer should use the returned Config.
+func Load(cfg string, flags []string) (*Config, error) {
+	return load(cfg, flags, true)
+}
+
+// LoadLenient is Load without the version check, for the callers which read only a part of the configuration, e.g. the
+// endure section. The included files still should have the same version as the root file.
+func LoadLenient(cfg string, flags []string) (*Config, error) {
+	return load(cfg, flags, false)
+}
+
+func load(cfg string, flags []string, versioned bool) (*Config, error) {
+	settings, err := readFile(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ver, ok := settings[versionKey]
+	switch {
+	case (!ok || ver == nil) && versioned:
+		return nil, fmt.Errorf("rr configuration file should contain a version e.g: version: 3")
+	case !ok || ver == nil:
+		ver = ""
+	}
+
+	if _, ok = ver.(string); !ok {
+		return nil, fmt.Errorf("version should be a string: `version: \"3\"`, actual type is: %T", ver)
+	}
+
+	origins := make(map[string]Origin)
+	fileOrigins(settings, "", Origin{Kind: OriginFile, File: cfg}, origins)
+
+	var secrets []string
+
+	// automatically inject ENV variables using ${ENV} pattern
+	_, err = expandEnv(settings, "", cfg, origins, &secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	includes, lists := stringList(settings[includeKey]), settings[includeListsKey]
+	// the include directives are consumed by the loader
+	delete(settings, includeKey)
+	delete(settings, includeListsKey)
+	dropOrigins(origins, includeKey)
+	dropOrigins(origins, includeListsKey)
+
+	listsStr, _ := lists.(string)
+	files, err := handleInclude(ver.(string), cfg, settings, origins, &secrets, includes, listsStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to handle includes: %w", err)
+	}
+
+	// override config Flags, they have the highest priority
+	for _, f := range flags {
+		key, val, errP := parseFlag(f)
+		if errP != nil {
+			return nil, errP
+		}
+
+		e := &expander{lookup: os.LookupEnv}
+		val, errP = e.expand(val)
+		if errP != nil {
+			return nil, fmt.Errorf("-o %s: %w", key, errP)
+		}
+
+		secrets = append(secrets, e.values...)
+
+		setKey(settings, key, val)
+		dropOrigins(origins, key)
+		origins[strings.ToLower(key)] = Origin{Kind: OriginOverride, Flag: f}
+	}
+
+	v := viper.New()
+	err = v.MergeConfigMap(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Config{
+		settings: settings,
+		v:        v,
+		origins:  origins,
+		files:    files,
+		secrets:  secrets,
+	}, nil
+}
+
+// Viper returns the resolved configuration.
+func (c *Config) Viper() *viper.Viper {
+	return c.v
+}
+
+// Settings returns the resolved configuration as a tree. Unlike viper.AllSettings, empty sections (e.g. `config: {}`)
+// and keys with dots are preserved. The returned map must not be modified.
+func (c *Config) Settings() map[string]any {
+	return c.settings
+}
+
+// Redact replaces the resolved secrets in the string, e.g. in a dumped value.
+func (c *Config) Redact(s string) string {
+	return redact(s, c.secrets)
+}
+
+// RedactError hides the resolved secrets in the error message.
+func (c *Config) RedactError(err error) error {
+	if err == nil || len(c.secrets) == 0 {
+		return err
+	}
+
+	msg := c.Redact(err.Error())
+	if msg == err.Error() {
+		return err
+	}
+
+	return &redactedError{err: err, msg: msg}
+}
+
+// Files returns the root configuration file and all the included files in the order of precedence.
+func (c *Config) Files() []string {
+	return c.files
+}
+
+// Origin returns the origin of the value by its key in the dot notation, e.g. http.pool.num_workers.
+func (c *Config) Origin(key string) (Origin, bool) {
+	o, ok := c.origins[strings.ToLower(key)]
+	return o, ok
+}
+
+// readFile reads a YAML (or JSON) configuration file. Keys are case-insensitive, the same as in viper.
+func readFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := make(map[string]any)
+	err = yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return normalize(doc).(map[string]any), nil
+}
+
+// normalize lowercases the keys and converts map[any]any produced for the non-string keys into map[string]any.
+func normalize(val any) any {
+	switch t := val.(type) {
+	case map[string]any:
+		res := make(map[string]any, len(t))
+		for k, v := range t {
+			res[strings.ToLower(k)] = normalize(v)
+		}
+
+		return res
+	case map[any]any:
+		res := make(map[string]any, len(t))
+		for k, v := range t {
+			res[strings.ToLower(fmt.Sprint(k))] = normalize(v)
+		}
+
+		return res
+	case []any:
+		for i := range t {
+			t[i] = normalize(t[i])
+		}
+
+		return t
+	default:
+		return val
+	}
+}
+
+// stringList converts the include directive (a list or a space separated string) into a list of patterns.
+func stringList(val any) []string {
+	switch t := val.(type) {
+	case string:
+		return strings.Fields(t)
+	case []any:
+		res := make([]string, 0, len(t))
+		for i := range t {
+			res = append(res, fmt.Sprint(t[i]))
+		}
+
+		return res
+	default:
+		return nil
+	}
+}
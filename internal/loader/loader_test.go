@@ -0,0 +1,83 @@
+package loader_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/roadrunner-server/roadrunner/v2025/container"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/configurer"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/loader"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Origins(t *testing.T) {
+	c, err := loader.Load("test/include1/.rr.yaml", []string{"logs.level=info"})
+	require.NoError(t, err)
+
+	o, ok := c.Origin("server.command")
+	require.True(t, ok)
+	assert.Equal(t, loader.OriginFile, o.Kind)
+	assert.Equal(t, "test/include1/.rr.yaml", o.File)
+
+	o, ok = c.Origin("rpc.listen")
+	require.True(t, ok)
+	assert.Equal(t, loader.OriginInclude, o.Kind)
+	assert.Equal(t, "test/include1/.rr-include.yaml", o.File)
+
+	o, ok = c.Origin("logs.level")
+	require.True(t, ok)
+	assert.Equal(t, loader.OriginOverride, o.Kind)
+	assert.Equal(t, "info", c.Viper().GetString("logs.level"))
+}
+
+func TestLoad_MissingVersion(t *testing.T) {
+	_, err := loader.Load("test/no-version.yaml", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "should contain a version")
+}
+
+func TestLoadLenient_MissingVersion(t *testing.T) {
+	c, err := loader.LoadLenient("test/no-version.yaml", []string{"endure.log_level=debug"})
+	require.NoError(t, err)
+	assert.Equal(t, "debug", c.Viper().GetString("endure.log_level"))
+}
+
+// the serve command, lib.NewRR, the endure container, the config plugin and the RPC clients must see the same values
+func TestLoad_SameValuesForAllConsumers(t *testing.T) {
+	overrides := []string{"endure.log_level=debug", "rpc.listen=tcp://127.0.0.1:6010"}
+
+	c, err := loader.Load("test/shared/.rr.yaml", overrides)
+	require.NoError(t, err)
+
+	// endure container configuration: the include and the -o override are taken into account
+	containerCfg, err := container.NewConfig("test/shared/.rr.yaml", overrides...)
+	require.NoError(t, err)
+	assert.Equal(t, "debug", containerCfg.LogLevel)
+	assert.Equal(t, time.Second*5, containerCfg.GracePeriod)
+	assert.True(t, containerCfg.PrintGraph)
+
+	// the config plugin serves the resolved configuration
+	p := &configurer.Plugin{Config: c}
+	require.NoError(t, p.Init())
+
+	pluginCfg := &container.Config{}
+	require.NoError(t, p.UnmarshalKey("endure", pluginCfg))
+	assert.Equal(t, containerCfg.LogLevel, pluginCfg.LogLevel)
+	assert.Equal(t, containerCfg.GracePeriod, pluginCfg.GracePeriod)
+	assert.Equal(t, containerCfg.PrintGraph, pluginCfg.PrintGraph)
+
+	fromLoader, err := container.ParseConfig(c.Viper())
+	require.NoError(t, err)
+	assert.Equal(t, containerCfg, fromLoader)
+
+	// RPC clients
+	assert.Equal(t, "tcp://127.0.0.1:6010", c.Viper().GetString("rpc.listen"))
+	assert.Equal(t, "tcp://127.0.0.1:6010", p.Get("rpc.listen"))
+
+	// empty sections enable plugins and must survive
+	assert.True(t, p.Has("informer"))
+	assert.Equal(t, map[string]any{}, c.Settings()["informer"])
+	// the include directive is consumed by the loader
+	assert.False(t, p.Has("include"))
+}
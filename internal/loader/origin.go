@@ -0,0 +1,84 @@
+package loader
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OriginKind tells where a configuration value came from.
+type OriginKind string
+
+const (
+	// OriginFile - the value is defined in the root configuration file.
+	OriginFile OriginKind = "file"
+	// OriginInclude - the value is defined in one of the included files.
+	OriginInclude OriginKind = "include"
+	// OriginEnv - the value is taken from the environment variable(s).
+	OriginEnv OriginKind = "env"
+	// OriginDefault - the referenced environment variable is not set, the default value is used, e.g. ${PORT:-8080}.
+	OriginDefault OriginKind = "default"
+	// OriginSecret - the value contains the secret read from a file, e.g. ${file:/run/secrets/db_password}.
+	OriginSecret OriginKind = "secret"
+	// OriginOverride - the value is set by the -o flag.
+	OriginOverride OriginKind = "override"
+)
+
+// Origin describes the source of a single configuration value.
+type Origin struct {
+	Kind OriginKind
+	// File where the value (or the env reference) is defined, empty for the overrides.
+	File string
+	// Env contains the names of the referenced environment variables.
+	Env []string
+	// Secrets contains the paths of the referenced secret files.
+	Secrets []string
+	// Flag is the -o flag which set the value.
+	Flag string
+}
+
+// String returns a human-readable representation of the origin.
+func (o Origin) String() string {
+	switch o.Kind {
+	case OriginFile, OriginInclude:
+		return fmt.Sprintf("%s %s", o.Kind, o.File)
+	case OriginEnv, OriginDefault:
+		return fmt.Sprintf("%s %s (%s)", o.Kind, strings.Join(o.Env, ", "), o.File)
+	case OriginSecret:
+		return fmt.Sprintf("secret %s (%s)", strings.Join(o.Secrets, ", "), o.File)
+	case OriginOverride:
+		return fmt.Sprintf("override -o %s", o.Flag)
+	default:
+		return "unknown"
+	}
+}
+
+// fileOrigins records the origin of every leaf value (empty sections included).
+func fileOrigins(val any, key string, o Origin, origins map[string]Origin) {
+	if m, ok := val.(map[string]any); ok && (len(m) > 0 || key == "") {
+		for k, v := range m {
+			fileOrigins(v, joinKey(key, k), o, origins)
+		}
+
+		return
+	}
+
+	origins[key] = o
+}
+
+// dropOrigins removes the origins of the key and all its nested keys, since the whole value was replaced.
+func dropOrigins(origins map[string]Origin, key string) {
+	key = strings.ToLower(key)
+	for k := range origins {
+		if k == key || strings.HasPrefix(k, key+".") {
+			delete(origins, k)
+		}
+	}
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
@@ -0,0 +1,121 @@
+package loader
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+const (
+	// EnvSecretsDir is the environment variable with the directory the ${secretdir:name} references are resolved against.
+	EnvSecretsDir string = "RR_SECRETS_DIR"
+	// default Docker and Kubernetes secrets location
+	defaultSecretsDir string = "/run/secrets"
+
+	// ${file:/run/secrets/db_password}
+	secretFile string = "file"
+	// ${secretdir:db_password}
+	secretDir string = "secretdir"
+
+	redacted string = "<redacted>"
+)
+
+// secretRef reports whether the ${...} reference content is a secret reference and returns its kind and the path or the
+// name of the secret. ${file:-x} is still the regular variable named file with the default value.
+func secretRef(expr string) (string, string, bool) {
+	kind, ref, ok := strings.Cut(expr, ":")
+	if !ok || ref == "" || (kind != secretFile && kind != secretDir) {
+		return "", "", false
+	}
+
+	if strings.ContainsRune("-=?+", rune(ref[0])) {
+		return "", "", false
+	}
+
+	return kind, ref, true
+}
+
+// SecretsDir returns the directory with the secrets files, RR_SECRETS_DIR or /run/secrets by default.
+func SecretsDir() string {
+	if dir := os.Getenv(EnvSecretsDir); dir != "" {
+		return dir
+	}
+
+	return defaultSecretsDir
+}
+
+// secretPath returns the path to the file referenced by ${file:path} or ${secretdir:name}.
+func secretPath(kind, ref string) (string, error) {
+	if kind == secretFile {
+		return ref, nil
+	}
+
+	// the name must not escape the secrets directory
+	if ref != filepath.Base(ref) || ref == "." || ref == ".." {
+		return "", fmt.Errorf("invalid secret name %q: should be a file name inside the secrets directory", ref)
+	}
+
+	return filepath.Join(SecretsDir(), ref), nil
+}
+
+// readSecret reads the secret from the file, the trailing newlines are trimmed.
+func readSecret(path string) (string, error) {
+	// follow the symlinks, Kubernetes mounts the secrets as the links to the ..data directory
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("secret %s: %w", path, err)
+	}
+
+	if !fi.Mode().IsRegular() {
+		return "", fmt.Errorf("secret %s: not a regular file", path)
+	}
+
+	// nobody except the owner and the group should be able to change the secret
+	if runtime.GOOS != "windows" && fi.Mode().Perm()&0o002 != 0 {
+		return "", fmt.Errorf("secret %s: insecure permissions %#o, the file should not be writable by others", path, fi.Mode().Perm())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("secret %s: %w", path, err)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// redact replaces the secret values in the string, the longest secrets are replaced first.
+func redact(s string, secrets []string) string {
+	if len(secrets) == 0 {
+		return s
+	}
+
+	sorted := slices.Clone(secrets)
+	slices.SortFunc(sorted, func(a, b string) int {
+		return len(b) - len(a)
+	})
+
+	for _, secret := range sorted {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+
+	return s
+}
+
+// redactedError hides the secrets in the error message, the original error is still available via errors.Is/As.
+type redactedError struct {
+	err error
+	msg string
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,4 @@
+// Package migrate upgrades the RoadRunner configuration files written for the older configuration versions (1.0, 2.x)
+// to the version 3 layout. The migration works on the YAML node tree, so the comments, the key order and the ${ENV}
+// references are preserved where possible.
+package migrate
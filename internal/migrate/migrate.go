@@ -0,0 +1,166 @@
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/roadrunner-server/errors"
+	"go.yaml.in/yaml/v3"
+)
+
+// CurrentVersion is the configuration version produced by the migration.
+const CurrentVersion string = "3"
+
+const versionKey string = "version"
+
+// Warning describes an option which was removed or could not be migrated automatically.
+type Warning struct {
+	// Path of the option in the original configuration, e.g. http.cache.
+	Path    string
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Path, w.Message)
+}
+
+type warnings []Warning
+
+func (w *warnings) add(path, format string, args ...any) {
+	*w = append(*w, Warning{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// Migrate rewrites the configuration into the version 3 layout: the keys are renamed, the sections are moved and the
+// removed options are dropped with a warning. A configuration which is already at the current version is returned
+// as is.
+func Migrate(data []byte) ([]byte, []Warning, error) {
+	const op = errors.Op("config_migrate")
+
+	doc := &yaml.Node{}
+	err := yaml.Unmarshal(data, doc)
+	if err != nil {
+		return nil, nil, errors.E(op, err)
+	}
+
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, errors.E(op, errors.Str("configuration should be a YAML mapping"))
+	}
+
+	root := doc.Content[0]
+
+	ver, err := detectVersion(root)
+	if err != nil {
+		return nil, nil, errors.E(op, err)
+	}
+
+	var w warnings
+
+	switch ver {
+	case CurrentVersion:
+		return data, nil, nil
+	case "1":
+		migrateV1(root, &w)
+		// 1.0 -> 2.x -> 3
+		migrateV2(root, &w)
+	case "2":
+		migrateV2(root, &w)
+	}
+
+	setVersion(root)
+
+	out, err := encode(doc)
+	if err != nil {
+		return nil, nil, errors.E(op, err)
+	}
+
+	return out, w, nil
+}
+
+// detectVersion returns the major configuration version. The configurations before 2.7 had no version key, 1.0 is
+// recognized by its layout (e.g. http.workers).
+func detectVersion(root *yaml.Node) (string, error) {
+	if v := get(root, versionKey); v != nil {
+		switch major, _, _ := strings.Cut(v.Value, "."); major {
+		case "1", "2", CurrentVersion:
+			return major, nil
+		default:
+			return "", errors.Errorf("unsupported configuration version %q", v.Value)
+		}
+	}
+
+	if get(get(root, "http"), "workers") != nil || get(get(root, "rpc"), "enable") != nil {
+		return "1", nil
+	}
+
+	for _, key := range []string{"env", "headers", "health", "limit", "static"} {
+		if get(root, key) != nil {
+			return "1", nil
+		}
+	}
+
+	return "2", nil
+}
+
+// setVersion sets the version to the current one, the version key goes first.
+func setVersion(root *yaml.Node) {
+	if v := get(root, versionKey); v != nil {
+		v.Value = CurrentVersion
+		v.Tag = "!!str"
+		v.Style = yaml.DoubleQuotedStyle
+
+		return
+	}
+
+	k := str(versionKey)
+	// the comment at the top of the file belongs to the file, not to the first section
+	if len(root.Content) > 0 {
+		k.HeadComment, root.Content[0].HeadComment = root.Content[0].HeadComment, ""
+	}
+
+	root.Content = append([]*yaml.Node{k, {Kind: yaml.ScalarNode, Tag: "!!str", Value: CurrentVersion, Style: yaml.DoubleQuotedStyle}}, root.Content...)
+}
+
+func encode(doc *yaml.Node) ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+
+	err := enc.Encode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	err = enc.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return separateSections(buf.Bytes()), nil
+}
+
+// separateSections puts the blank lines between the top-level sections back, the YAML encoder drops them.
+func separateSections(data []byte) []byte {
+	lines := strings.Split(string(data), "\n")
+	res := make([]string, 0, len(lines)+16)
+
+	for i, line := range lines {
+		topLevel := line != "" && line[0] != ' ' && line[0] != '#' && line[0] != '-'
+		if topLevel && i > 0 {
+			// keep the head comments attached to the section
+			start := len(res)
+			for start > 0 && strings.HasPrefix(res[start-1], "#") {
+				start--
+			}
+
+			if start > 0 && res[start-1] != "" {
+				res = append(res[:start], append([]string{""}, res[start:]...)...)
+			}
+		}
+
+		res = append(res, line)
+	}
+
+	return []byte(strings.Join(res, "\n"))
+}
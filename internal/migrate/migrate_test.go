@@ -0,0 +1,90 @@
+package migrate_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/roadrunner-server/roadrunner/v2025/internal/migrate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		wantWarnings []string
+	}{
+		{
+			name: "v1",
+			wantWarnings: []string{
+				"http.http2.enabled: removed, HTTP/2 is enabled automatically when TLS is configured",
+				"reload: the reload plugin was removed, restart the workers with `rr reset` from your file watcher",
+			},
+		},
+		{
+			name: "v2",
+			wantWarnings: []string{
+				"reload: the reload plugin was removed, restart the workers with `rr reset` from your file watcher",
+				"http.cache: the cache middleware is no longer bundled with RoadRunner",
+				"server.relay_timeout: removed, the relay timeout is no longer configurable",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile("test/" + tt.name + ".yaml")
+			require.NoError(t, err)
+
+			want, err := os.ReadFile("test/" + tt.name + ".migrated.yaml")
+			require.NoError(t, err)
+
+			out, warnings, err := migrate.Migrate(data)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(out))
+
+			got := make([]string, 0, len(warnings))
+			for _, w := range warnings {
+				got = append(got, w.String())
+			}
+
+			assert.Equal(t, tt.wantWarnings, got)
+
+			// the migrated configuration is up to date
+			again, warnings, err := migrate.Migrate(out)
+			require.NoError(t, err)
+			assert.Empty(t, warnings)
+			assert.Equal(t, out, again)
+		})
+	}
+}
+
+func TestMigrate_Errors(t *testing.T) {
+	_, _, err := migrate.Migrate([]byte("version: \"4\"\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unsupported configuration version "4"`)
+
+	_, _, err = migrate.Migrate([]byte("- a\n- b\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "configuration should be a YAML mapping")
+}
+
+func TestMigrate_DisabledRPC(t *testing.T) {
+	out, warnings, err := migrate.Migrate([]byte("rpc:\n  enable: false\n  listen: tcp://127.0.0.1:6001\nhttp:\n  workers:\n    command: php worker.php\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "version: \"3\"\n\nhttp: {}\n\nserver:\n  command: php worker.php\n", string(out))
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "rpc.enable", warnings[0].Path)
+}
+
+func TestMigrate_ExistingSupervisor(t *testing.T) {
+	in := "http:\n  workers:\n    command: php worker.php\n  pool:\n    supervisor:\n      max_worker_memory: 256\n" +
+		"limit:\n  interval: 1\n  services:\n    http:\n      maxMemory: 100\n      execTTL: 60\n"
+
+	out, warnings, err := migrate.Migrate([]byte(in))
+	require.NoError(t, err)
+
+	assert.Equal(t, "version: \"3\"\n\nhttp:\n  pool:\n    supervisor:\n      max_worker_memory: 256\n      exec_ttl: 60s\n      watch_tick: 1s\n\n"+
+		"server:\n  command: php worker.php\n", string(out))
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "limit.services.http.max_worker_memory: http.pool.supervisor.max_worker_memory is already set, removed", warnings[0].String())
+}
@@ -0,0 +1,153 @@
+package migrate
+
+import (
+	"go.yaml.in/yaml/v3"
+)
+
+// get returns the value of the key in the mapping node, nil if the key (or the mapping) doesn't exist.
+func get(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// take removes the key from the mapping node and returns the key and the value nodes.
+func take(m *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			k, v := m.Content[i], m.Content[i+1]
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+
+			return k, v
+		}
+	}
+
+	return nil, nil
+}
+
+// put adds the key/value pair to the mapping node, replacing the existing value.
+func put(m *yaml.Node, k, v *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == k.Value {
+			m.Content[i+1] = v
+			return
+		}
+	}
+
+	m.Content = append(m.Content, k, v)
+}
+
+// move moves the key from one mapping to another under the new name, the comments are moved as well.
+func move(from *yaml.Node, key string, to *yaml.Node, newKey string) bool {
+	k, v := take(from, key)
+	if k == nil {
+		return false
+	}
+
+	k.Value = newKey
+	put(to, k, v)
+
+	return true
+}
+
+// merge adds the keys of the from mapping to the to mapping, the keys already set in the to mapping are kept. The
+// conflicting keys are returned.
+func merge(to, from *yaml.Node) []string {
+	var conflicts []string
+
+	for i := 0; i+1 < len(from.Content); i += 2 {
+		if get(to, from.Content[i].Value) != nil {
+			conflicts = append(conflicts, from.Content[i].Value)
+			continue
+		}
+
+		to.Content = append(to.Content, from.Content[i], from.Content[i+1])
+	}
+
+	return conflicts
+}
+
+// rename renames the keys of the mapping node in place.
+func rename(m *yaml.Node, names map[string]string) {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if n, ok := names[m.Content[i].Value]; ok {
+			m.Content[i].Value = n
+		}
+	}
+}
+
+// ensureMap returns the mapping stored under the key, creating it if needed.
+func ensureMap(m *yaml.Node, key string) *yaml.Node {
+	if v := get(m, key); v != nil && v.Kind == yaml.MappingNode {
+		return v
+	}
+
+	v := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	put(m, str(key), v)
+
+	return v
+}
+
+// appendUnique appends the value to the sequence stored under the key, creating the sequence if needed.
+func appendUnique(m *yaml.Node, key, val string) {
+	seq := get(m, key)
+	if seq == nil || seq.Kind != yaml.SequenceNode {
+		seq = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+		put(m, str(key), seq)
+	}
+
+	item := str(val)
+	for _, existing := range seq.Content {
+		if existing.Value == val {
+			return
+		}
+
+		// follow the quoting style of the list
+		item.Style = existing.Style
+	}
+
+	seq.Content = append(seq.Content, item)
+}
+
+// seconds converts the plain number of seconds (e.g. 60) into a duration (60s).
+func seconds(m *yaml.Node, keys ...string) {
+	for _, key := range keys {
+		if v := get(m, key); v != nil && v.Kind == yaml.ScalarNode && v.Tag == "!!int" {
+			v.Value += "s"
+			v.Tag = "!!str"
+		}
+	}
+}
+
+func str(val string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: val}
+}
+
+func keys(m *yaml.Node) []string {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	res := make([]string, 0, len(m.Content)/2)
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		res = append(res, m.Content[i].Value)
+	}
+
+	return res
+}
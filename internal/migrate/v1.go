@@ -0,0 +1,134 @@
+package migrate
+
+import (
+	"go.yaml.in/yaml/v3"
+)
+
+// 1.0 -> 2.x renames: camelCase keys became snake_case
+var (
+	poolKeysV1 = map[string]string{ //nolint:gochecknoglobals
+		"numWorkers":      "num_workers",
+		"maxJobs":         "max_jobs",
+		"allocateTimeout": "allocate_timeout",
+		"destroyTimeout":  "destroy_timeout",
+	}
+
+	supervisorKeysV1 = map[string]string{ //nolint:gochecknoglobals
+		"maxMemory": "max_worker_memory",
+		"TTL":       "ttl",
+		"idleTTL":   "idle_ttl",
+		"execTTL":   "exec_ttl",
+	}
+
+	corsKeysV1 = map[string]string{ //nolint:gochecknoglobals
+		"allowedOrigin":    "allowed_origin",
+		"allowedHeaders":   "allowed_headers",
+		"allowedMethods":   "allowed_methods",
+		"allowCredentials": "allow_credentials",
+		"exposedHeaders":   "exposed_headers",
+		"maxAge":           "max_age",
+	}
+)
+
+// migrateV1 converts the RoadRunner 1.x layout into the 2.x one.
+func migrateV1(root *yaml.Node, w *warnings) {
+	http := get(root, "http")
+
+	// http.workers -> server + http.pool
+	if _, workers := take(http, "workers"); workers != nil {
+		server := ensureMap(root, "server")
+		for _, key := range []string{"command", "relay", "user"} {
+			move(workers, key, server, key)
+		}
+
+		if pool := get(workers, "pool"); pool != nil {
+			rename(pool, poolKeysV1)
+			seconds(pool, "allocate_timeout", "destroy_timeout")
+			move(workers, "pool", http, "pool")
+		}
+
+		for _, key := range keys(workers) {
+			w.add("http.workers."+key, "unknown option, removed")
+		}
+	}
+
+	if http != nil {
+		rename(http, map[string]string{
+			"maxRequestSize": "max_request_size",
+			"trustedSubnets": "trusted_subnets",
+		})
+
+		if h2 := get(http, "http2"); h2 != nil {
+			rename(h2, map[string]string{"maxConcurrentStreams": "max_concurrent_streams"})
+			if k, _ := take(h2, "enabled"); k != nil {
+				w.add("http.http2.enabled", "removed, HTTP/2 is enabled automatically when TLS is configured")
+			}
+		}
+
+		if ssl := get(http, "ssl"); ssl != nil {
+			rename(ssl, map[string]string{"rootCa": "root_ca"})
+			if k, port := take(ssl, "port"); k != nil {
+				k.Value = "address"
+				port.Value = "0.0.0.0:" + port.Value
+				port.Tag = "!!str"
+				put(ssl, k, port)
+			}
+		}
+	}
+
+	// env -> server.env
+	if get(root, "env") != nil {
+		move(root, "env", ensureMap(root, "server"), "env")
+	}
+
+	// headers and static became HTTP middleware
+	if k, headers := take(root, "headers"); k != nil {
+		http = ensureMap(root, "http")
+		rename(get(headers, "cors"), corsKeysV1)
+		put(http, k, headers)
+		appendUnique(http, "middleware", "headers")
+	}
+
+	if k, static := take(root, "static"); k != nil {
+		http = ensureMap(root, "http")
+		put(http, k, static)
+		appendUnique(http, "middleware", "static")
+	}
+
+	// limit -> http.pool.supervisor
+	if _, limit := take(root, "limit"); limit != nil {
+		services := get(limit, "services")
+		if _, limits := take(services, "http"); limits != nil {
+			supervisor := ensureMap(ensureMap(ensureMap(root, "http"), "pool"), "supervisor")
+			rename(limits, supervisorKeysV1)
+			seconds(limits, "ttl", "idle_ttl", "exec_ttl")
+
+			if k, interval := take(limit, "interval"); k != nil {
+				k.Value = "watch_tick"
+				limits.Content = append(limits.Content, k, interval)
+				seconds(limits, "watch_tick")
+			}
+
+			// the supervisor might be configured already, its options take precedence
+			for _, key := range merge(supervisor, limits) {
+				w.add("limit.services.http."+key, "http.pool.supervisor.%s is already set, removed", key)
+			}
+		}
+
+		for _, key := range keys(services) {
+			w.add("limit.services."+key, "only the http service limits can be migrated, configure the supervisor of the %s pool manually", key)
+		}
+	}
+
+	// health -> status
+	if _, health := take(root, "health"); health != nil {
+		move(health, "address", ensureMap(root, "status"), "address")
+	}
+
+	if rpc := get(root, "rpc"); rpc != nil {
+		if _, enable := take(rpc, "enable"); enable != nil && enable.Value == "false" {
+			take(root, "rpc")
+			w.add("rpc.enable", "RPC is enabled by the rpc section, the disabled rpc section was removed")
+		}
+	}
+}
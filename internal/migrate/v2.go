@@ -0,0 +1,82 @@
+package migrate
+
+import (
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// removed plugins and options
+var removedV2 = []struct { //nolint:gochecknoglobals
+	path    []string
+	message string
+}{
+	{path: []string{"reload"}, message: "the reload plugin was removed, restart the workers with `rr reset` from your file watcher"},
+	{path: []string{"tcp"}, message: "the tcp plugin is no longer bundled with RoadRunner"},
+	{path: []string{"http", "cache"}, message: "the cache middleware is no longer bundled with RoadRunner"},
+	{path: []string{"server", "relay_timeout"}, message: "removed, the relay timeout is no longer configurable"},
+}
+
+// migrateV2 converts the RoadRunner 2.x layout into the version 3 one.
+func migrateV2(root *yaml.Node, w *warnings) {
+	for _, r := range removedV2 {
+		parent := root
+		for _, p := range r.path[:len(r.path)-1] {
+			parent = get(parent, p)
+		}
+
+		if k, _ := take(parent, r.path[len(r.path)-1]); k != nil {
+			w.add(strings.Join(r.path, "."), "%s", r.message)
+		}
+	}
+
+	// http.otel -> otel + the otel middleware
+	if http := get(root, "http"); get(http, "otel") != nil {
+		if get(root, "otel") != nil {
+			take(http, "otel")
+			w.add("http.otel", "the top-level otel section already exists, http.otel was removed")
+		} else {
+			move(http, "otel", root, "otel")
+		}
+
+		appendUnique(http, "middleware", "otel")
+	}
+
+	// kafka.addr -> kafka.brokers
+	if kafka := get(root, "kafka"); kafka != nil {
+		if k, addr := take(kafka, "addr"); k != nil {
+			k.Value = "brokers"
+			if addr.Kind == yaml.ScalarNode {
+				addr = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{addr}}
+			}
+
+			put(kafka, k, addr)
+		}
+	}
+
+	// the driver options before 2.7 were not nested under the config key
+	nestDriverConfig(get(get(root, "jobs"), "pipelines"))
+	nestDriverConfig(get(root, "kv"))
+}
+
+// nestDriverConfig moves the driver options of every section (pipeline, storage) under the config key.
+func nestDriverConfig(sections *yaml.Node) {
+	if sections == nil || sections.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 1; i < len(sections.Content); i += 2 {
+		section := sections.Content[i]
+		if get(section, "driver") == nil {
+			continue
+		}
+
+		for _, key := range keys(section) {
+			if key == "driver" || key == "config" {
+				continue
+			}
+
+			move(section, key, ensureMap(section, "config"), key)
+		}
+	}
+}
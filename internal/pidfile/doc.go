@@ -0,0 +1,5 @@
+// Package pidfile manages the RoadRunner pid file: the file is created by the
+// serve command and holds an advisory lock for the lifetime of the server, so
+// a second instance with the same pid file is refused. The commands signaling
+// the server check the lock: the PID from the unlocked file is not the server.
+package pidfile
@@ -0,0 +1,23 @@
+//go:build !windows
+
+package pidfile
+
+import (
+	stderr "errors"
+	"os"
+	"syscall"
+)
+
+// lock takes the advisory lock, the lock is released by the kernel when the process exits.
+func lock(f *os.File) error {
+	err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB) //nolint:gosec
+	if stderr.Is(err, syscall.EWOULDBLOCK) {
+		return ErrLocked
+	}
+
+	return err
+}
+
+func unlock(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN) //nolint:gosec
+}
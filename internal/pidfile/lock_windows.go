@@ -0,0 +1,29 @@
+//go:build windows
+
+package pidfile
+
+import (
+	stderr "errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lock takes the lock on the byte far beyond the end of the file: the locked range can't be read by other processes
+// and `rr stop` still has to read the PID.
+func lock(f *os.File) error {
+	ol := &windows.Overlapped{OffsetHigh: 0x7fffffff}
+
+	err := windows.LockFileEx(windows.Handle(f.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, ol)
+	if stderr.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return ErrLocked
+	}
+
+	return err
+}
+
+func unlock(f *os.File) error {
+	ol := &windows.Overlapped{OffsetHigh: 0x7fffffff}
+
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, ol)
+}
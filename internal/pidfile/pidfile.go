@@ -0,0 +1,221 @@
+package pidfile
+
+import (
+	stderr "errors"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/roadrunner-server/errors"
+)
+
+// DefaultPath is used by the `-p` flag without the value and by `rr stop` when the pid file is not configured.
+const DefaultPath string = ".pid"
+
+// ErrLocked is returned when the pid file is held by another running instance.
+var ErrLocked = stderr.New("pid file is locked by another instance")
+
+// File is the locked pid file of the running server.
+type File struct {
+	path string
+	file *os.File
+}
+
+// Create writes the PID of the current process into the file and locks it. The file of the crashed instance is
+// reused, but the file locked by the running instance is left untouched.
+func Create(path string) (*File, error) {
+	const op = errors.Op("pidfile_create")
+
+	for {
+		f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644) //nolint:gosec
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+
+		err = lock(f)
+		if err != nil {
+			_ = f.Close()
+
+			if stderr.Is(err, ErrLocked) {
+				if pid, errR := Read(path); errR == nil {
+					return nil, errors.E(op, errors.Errorf("another RoadRunner instance is already running with PID %d, pid file: %s", pid, path))
+				}
+			}
+
+			return nil, errors.E(op, errors.Errorf("%s: %v", path, err))
+		}
+
+		// the previous owner removes the file under the lock, so the file opened before that is unlinked by now and its
+		// lock doesn't protect the path: open the new one
+		ok, err := linked(path, f)
+		if err != nil || !ok {
+			_ = f.Close()
+
+			if err != nil {
+				return nil, errors.E(op, err)
+			}
+
+			continue
+		}
+
+		err = writePID(f)
+		if err != nil {
+			_ = f.Close()
+			return nil, errors.E(op, err)
+		}
+
+		return &File{path: path, file: f}, nil
+	}
+}
+
+// linked reports whether the path still refers to the opened file.
+func linked(path string, f *os.File) (bool, error) {
+	st, err := os.Stat(path)
+	if err != nil {
+		if stderr.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	own, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	return os.SameFile(st, own), nil
+}
+
+// Adopt takes over the pid file locked by the previous process, the lock is shared with the passed descriptor. The file
+// keeps the PID of the previous process until Own is called: the previous process keeps serving when this one fails to
+// start.
+func Adopt(path string, f *os.File) *File {
+	return &File{path: path, file: f}
+}
+
+// Own writes the PID of the current process into the adopted file, called once the process is ready.
+func (f *File) Own() error {
+	const op = errors.Op("pidfile_own")
+
+	if err := writePID(f.file); err != nil {
+		return errors.E(op, err)
+	}
+
+	return nil
+}
+
+// File returns the locked file, e.g. to pass it to the new process on restart.
+func (f *File) File() *os.File {
+	return f.file
+}
+
+// Path returns the path to the pid file.
+func (f *File) Path() string {
+	return f.path
+}
+
+// Remove deletes the pid file and releases the lock, called on the clean shutdown.
+func (f *File) Remove() error {
+	// removed under the lock, so the starting instance can't lose its freshly written file
+	err := os.Remove(f.path)
+	errC := f.file.Close()
+
+	return stderr.Join(err, errC)
+}
+
+// Close releases the file without removing it, the pid file is owned by the new process after the restart.
+func (f *File) Close() error {
+	return f.file.Close()
+}
+
+// RemoveStale removes the pid file left by the crashed process. The file is removed under the lock, ErrLocked is
+// returned when the file is locked: the owner is still running even if the PID in the file is not (e.g. the new process
+// failed to start on the graceful restart).
+func RemoveStale(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	if err = lock(f); err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = unlock(f)
+	}()
+
+	return os.Remove(path)
+}
+
+func writePID(f *os.File) error {
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+
+	_, err := f.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0)
+
+	return err
+}
+
+// Read returns the PID written into the file.
+func Read(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, errors.Errorf("%s: invalid PID: %v", path, err)
+	}
+
+	return pid, nil
+}
+
+// Check returns an error when the process is not running or the PID from the file belongs to another program: the
+// running server holds the lock of its pid file, so the PID of the unlocked file is reused by the system.
+func Check(path string, pid int) error {
+	if !Alive(pid) {
+		return errors.Errorf("stale pid file: process with PID %d is not running", pid)
+	}
+
+	held, err := locked(path)
+	if err != nil {
+		return err
+	}
+
+	if !held {
+		return errors.Errorf("stale pid file: %s is not locked, PID %d belongs to another program", path, pid)
+	}
+
+	return nil
+}
+
+// locked reports whether the file is locked by another process, the lock is taken and released right away otherwise.
+func locked(path string) (bool, error) {
+	f, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	err = lock(f)
+	if err != nil {
+		if stderr.Is(err, ErrLocked) {
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	return false, unlock(f)
+}
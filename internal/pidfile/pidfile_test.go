@@ -0,0 +1,167 @@
+package pidfile_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/roadrunner-server/roadrunner/v2025/internal/pidfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rr.pid")
+
+	// the file left by the crashed instance is overwritten
+	require.NoError(t, os.WriteFile(path, []byte("123456789012"), 0o600))
+
+	pf, err := pidfile.Create(path)
+	require.NoError(t, err)
+
+	pid, err := pidfile.Read(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.Getpid(), pid)
+	assert.Equal(t, path, pf.Path())
+
+	require.NoError(t, pf.Remove())
+	assert.NoFileExists(t, path)
+}
+
+func TestCreateLocked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rr.pid")
+
+	pf, err := pidfile.Create(path)
+	require.NoError(t, err)
+
+	_, err = pidfile.Create(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "another RoadRunner instance is already running with PID "+strconv.Itoa(os.Getpid()))
+
+	// the file of the running instance is untouched
+	pid, err := pidfile.Read(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.Getpid(), pid)
+
+	// the lock is released with the file
+	require.NoError(t, pf.Remove())
+
+	pf, err = pidfile.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, pf.Remove())
+}
+
+// the file removed by the previous owner between the open and the lock is not used: the lock of the unlinked file
+// doesn't protect the path
+func TestCreateRemoved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rr.pid")
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for created := 0; created < 50; {
+				pf, err := pidfile.Create(path)
+				if err != nil {
+					// locked by another goroutine
+					continue
+				}
+
+				created++
+
+				st, errS := os.Stat(path)
+				if assert.NoError(t, errS) {
+					own, errF := pf.File().Stat()
+					require.NoError(t, errF)
+					assert.True(t, os.SameFile(st, own), "the locked file is not the one at the path")
+				}
+
+				assert.NoError(t, pf.Remove())
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rr.pid")
+
+	// the PID is running, but the file is not locked by it
+	require.NoError(t, os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())), 0o600))
+	err := pidfile.Check(path, os.Getpid())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not locked, PID")
+
+	pf, err := pidfile.Create(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pf.Remove() })
+
+	require.NoError(t, pidfile.Check(path, os.Getpid()))
+}
+
+func TestRemoveStale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rr.pid")
+
+	pf, err := pidfile.Create(path)
+	require.NoError(t, err)
+
+	// the running instance keeps its file
+	err = pidfile.RemoveStale(path)
+	require.ErrorIs(t, err, pidfile.ErrLocked)
+	assert.FileExists(t, path)
+
+	require.NoError(t, pf.Close())
+
+	require.NoError(t, pidfile.RemoveStale(path))
+	assert.NoFileExists(t, path)
+}
+
+func TestRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rr.pid")
+
+	_, err := pidfile.Read(path)
+	assert.True(t, os.IsNotExist(err))
+
+	require.NoError(t, os.WriteFile(path, []byte("42\n"), 0o600))
+	pid, err := pidfile.Read(path)
+	require.NoError(t, err)
+	assert.Equal(t, 42, pid)
+
+	require.NoError(t, os.WriteFile(path, []byte("rr"), 0o600))
+	_, err = pidfile.Read(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid PID")
+}
+
+func TestAdopt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rr.pid")
+
+	pf, err := pidfile.Create(path)
+	require.NoError(t, err)
+
+	// the descriptor passed to the new process shares the lock
+	require.NoError(t, os.WriteFile(path, []byte("1"), 0o600))
+	adopted := pidfile.Adopt(path, pf.File())
+
+	// the previous process owns the file until the new one is ready
+	pid, err := pidfile.Read(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, pid)
+
+	require.NoError(t, adopted.Own())
+
+	pid, err = pidfile.Read(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.Getpid(), pid)
+
+	_, err = pidfile.Create(path)
+	require.Error(t, err)
+
+	require.NoError(t, adopted.Remove())
+	assert.NoFileExists(t, path)
+}
@@ -0,0 +1,25 @@
+//go:build linux
+
+package pidfile
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+)
+
+// zombie reports whether the process has exited but is not reaped by its parent yet.
+func zombie(pid int) bool {
+	data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return false
+	}
+
+	// the state goes after the command name, which might contain spaces and parentheses
+	i := bytes.LastIndexByte(data, ')')
+	if i == -1 || i+2 >= len(data) {
+		return false
+	}
+
+	return data[i+2] == 'Z'
+}
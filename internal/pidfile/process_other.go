@@ -0,0 +1,7 @@
+//go:build !linux && !windows
+
+package pidfile
+
+func zombie(int) bool {
+	return false
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package pidfile
+
+import (
+	stderr "errors"
+	"syscall"
+)
+
+// Alive checks the process with the null signal. The process of another user can't be signaled, but it exists.
+func Alive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	if err != nil && !stderr.Is(err, syscall.EPERM) {
+		return false
+	}
+
+	return !zombie(pid)
+}
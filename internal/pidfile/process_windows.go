@@ -0,0 +1,19 @@
+//go:build windows
+
+package pidfile
+
+import (
+	"os"
+)
+
+// Alive checks the process by opening it, the exited process can't be found.
+func Alive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	_ = p.Release()
+
+	return true
+}
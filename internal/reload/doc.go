@@ -0,0 +1,5 @@
+// Package reload prepares the configuration reload: it re-reads and validates
+// the configuration, computes the per-plugin diff against the running one and
+// plans which changes are applied to the running plugins (the pool size, the
+// endure options used by serve) and which require the plugins restart.
+package reload
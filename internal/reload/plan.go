@@ -0,0 +1,126 @@
+package reload
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"strconv"
+)
+
+const (
+	endureSection string = "endure"
+	poolKey       string = "pool"
+	numWorkersKey string = "num_workers"
+)
+
+// containerOptions are the endure options read when the container is created. The other endure options are used by
+// serve (restart mode, profiler, watchdog plugins) and are applied without restarting the plugins.
+var containerOptions = []string{"grace_period", "log_level", "print_graph"} //nolint:gochecknoglobals
+
+// Plan describes how the changes are applied to the running server.
+type Plan struct {
+	// Restart lists the changes which require restarting the plugins. When it's empty, the changes are applied to the
+	// running plugins.
+	Restart []Change
+	// Reset lists the plugins whose only changes are the <section>.pool options other than num_workers: the plugins
+	// get the new configuration and their workers are reset.
+	Reset []string
+	// Scale is the new number of workers of the plugins whose only changes are in <section>.pool.
+	Scale map[string]int
+}
+
+// NewPlan sorts the changes returned by Diff into the ones applied to the running plugins and the ones requiring the
+// restart.
+func NewPlan(current, next map[string]any, changes []Change) *Plan {
+	p := &Plan{Scale: make(map[string]int)}
+
+	for _, c := range changes {
+		if c.Section == endureSection {
+			if containerChanged(section(current, c.Section), section(next, c.Section)) {
+				p.Restart = append(p.Restart, c)
+			}
+
+			continue
+		}
+
+		if c.Added || c.Removed {
+			p.Restart = append(p.Restart, c)
+			continue
+		}
+
+		options, size, ok := poolChanged(section(current, c.Section), section(next, c.Section))
+		if !ok {
+			p.Restart = append(p.Restart, c)
+			continue
+		}
+
+		if options {
+			p.Reset = append(p.Reset, c.Section)
+		}
+
+		if size > 0 {
+			p.Scale[c.Section] = size
+		}
+	}
+
+	return p
+}
+
+// containerChanged reports whether the endure options read by the container differ.
+func containerChanged(current, next map[string]any) bool {
+	for _, key := range containerOptions {
+		if !reflect.DeepEqual(current[key], next[key]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// poolChanged reports whether the sections differ only in the pool options. It returns whether the options other than
+// num_workers are changed and the new number of workers when it's changed. The number which is not set (the default) is
+// not known, the workers are reset instead.
+func poolChanged(current, next map[string]any) (bool, int, bool) {
+	if current == nil || next == nil {
+		return false, 0, false
+	}
+
+	currentPool, nextPool := maps.Clone(section(current, poolKey)), maps.Clone(section(next, poolKey))
+	if currentPool == nil {
+		currentPool = make(map[string]any)
+	}
+
+	if nextPool == nil {
+		nextPool = make(map[string]any)
+	}
+
+	current, next = maps.Clone(current), maps.Clone(next)
+	delete(current, poolKey)
+	delete(next, poolKey)
+
+	if !reflect.DeepEqual(current, next) {
+		return false, 0, false
+	}
+
+	var size int
+	if !reflect.DeepEqual(currentPool[numWorkersKey], nextPool[numWorkersKey]) {
+		// the number is taken from the environment variable as a string
+		n, err := strconv.Atoi(fmt.Sprint(nextPool[numWorkersKey]))
+		if err != nil || n <= 0 {
+			return true, 0, true
+		}
+
+		size = n
+	}
+
+	delete(currentPool, numWorkersKey)
+	delete(nextPool, numWorkersKey)
+
+	return !reflect.DeepEqual(currentPool, nextPool), size, true
+}
+
+// section returns the nested mapping, nil when the key is not set or is not a mapping.
+func section(settings map[string]any, key string) map[string]any {
+	m, _ := settings[key].(map[string]any)
+	return m
+}
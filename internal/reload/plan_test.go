@@ -0,0 +1,81 @@
+package reload_test
+
+import (
+	"testing"
+
+	"github.com/roadrunner-server/roadrunner/v2025/internal/reload"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlan(t *testing.T) {
+	current := map[string]any{
+		"endure": map[string]any{"grace_period": "30s", "restart_mode": "restart"},
+		"http":   map[string]any{"address": "127.0.0.1:8080", "pool": map[string]any{"num_workers": 4, "max_jobs": 64}},
+		"jobs":   map[string]any{"num_pollers": 2, "pool": map[string]any{"num_workers": 2}},
+		"grpc":   map[string]any{"listen": "tcp://127.0.0.1:9001", "pool": map[string]any{"num_workers": 2}},
+		"tcp":    map[string]any{"pool": map[string]any{"num_workers": 2}},
+	}
+
+	next := map[string]any{
+		// the options used by serve only
+		"endure": map[string]any{"grace_period": "30s", "restart_mode": "graceful", "watchdog_critical_plugins": []any{"http"}},
+		// the pool size only, the number might come from the environment variable
+		"http": map[string]any{"address": "127.0.0.1:8080", "pool": map[string]any{"num_workers": "8", "max_jobs": 64}},
+		"jobs": map[string]any{"num_pollers": 2, "pool": map[string]any{"num_workers": 1}},
+		// the pool size and the address
+		"grpc": map[string]any{"listen": "tcp://127.0.0.1:9002", "pool": map[string]any{"num_workers": 4}},
+		// the default pool size is not known
+		"tcp": map[string]any{"pool": map[string]any{}},
+	}
+
+	plan := reload.NewPlan(current, next, reload.Diff(current, next))
+	assert.Equal(t, []reload.Change{{Section: "grpc"}}, plan.Restart)
+	assert.Equal(t, []string{"tcp"}, plan.Reset)
+	assert.Equal(t, map[string]int{"http": 8, "jobs": 1}, plan.Scale)
+}
+
+func TestPlanReset(t *testing.T) {
+	current := map[string]any{
+		"http": map[string]any{"pool": map[string]any{"num_workers": 4, "max_jobs": 64}},
+		"jobs": map[string]any{"num_pollers": 2},
+		"grpc": map[string]any{"pool": map[string]any{"supervisor": map[string]any{"max_worker_memory": 100}}},
+	}
+
+	next := map[string]any{
+		// the pool options and the size
+		"http": map[string]any{"pool": map[string]any{"num_workers": 2, "max_jobs": 100}},
+		// the pool section added
+		"jobs": map[string]any{"num_pollers": 2, "pool": map[string]any{"max_jobs": 10}},
+		"grpc": map[string]any{"pool": map[string]any{"supervisor": map[string]any{"max_worker_memory": 200}}},
+	}
+
+	plan := reload.NewPlan(current, next, reload.Diff(current, next))
+	assert.Empty(t, plan.Restart)
+	assert.Equal(t, []string{"grpc", "http", "jobs"}, plan.Reset)
+	assert.Equal(t, map[string]int{"http": 2}, plan.Scale)
+}
+
+func TestPlanRestart(t *testing.T) {
+	current := map[string]any{
+		"endure": map[string]any{"log_level": "error"},
+		"http":   map[string]any{"pool": map[string]any{"num_workers": 4}},
+	}
+
+	next := map[string]any{
+		"endure": map[string]any{"log_level": "debug"},
+		"jobs":   map[string]any{"pool": map[string]any{"num_workers": 4}},
+	}
+
+	plan := reload.NewPlan(current, next, reload.Diff(current, next))
+	assert.Equal(t, []reload.Change{
+		{Section: "endure"},
+		{Section: "http", Removed: true},
+		{Section: "jobs", Added: true},
+	}, plan.Restart)
+	assert.Empty(t, plan.Scale)
+
+	// the endure section added with the options used by serve only
+	plan = reload.NewPlan(map[string]any{}, map[string]any{"endure": map[string]any{"restart_mode": "graceful"}},
+		[]reload.Change{{Section: "endure", Added: true}})
+	assert.Empty(t, plan.Restart)
+}
@@ -0,0 +1,95 @@
+package reload
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/roadrunner-server/errors"
+	"github.com/roadrunner-server/roadrunner/v2025/container"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/loader"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/schema"
+)
+
+// Change is the changed top-level section of the configuration, usually a plugin.
+type Change struct {
+	Section string
+	Added   bool
+	Removed bool
+}
+
+func (c Change) String() string {
+	switch {
+	case c.Added:
+		return c.Section + " (added)"
+	case c.Removed:
+		return c.Section + " (removed)"
+	default:
+		return c.Section
+	}
+}
+
+// Load reads the configuration and validates it against the schema and the container options. The error lists all
+// the problems found.
+func Load(cfgFile string, overrides []string) (*loader.Config, error) {
+	const op = errors.Op("reload_load")
+
+	c, err := loader.Load(cfgFile, overrides)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	validator, err := schema.NewValidator()
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	violations, err := validator.Validate(c.Settings())
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	if len(violations) > 0 {
+		problems := make([]string, 0, len(violations))
+		for _, vl := range violations {
+			problems = append(problems, fmt.Sprintf("%s: %s", strings.Join(vl.Path, "."), c.Redact(vl.Message)))
+		}
+
+		return nil, errors.E(op, errors.Errorf("%s: %d schema violation(s) found:\n%s", cfgFile, len(violations), strings.Join(problems, "\n")))
+	}
+
+	_, err = container.ParseConfig(c.Viper())
+	if err != nil {
+		return nil, errors.E(op, c.RedactError(err))
+	}
+
+	return c, nil
+}
+
+// Diff compares the top-level sections of the configurations, the changes are sorted by the section name.
+func Diff(current, next map[string]any) []Change {
+	var changes []Change
+
+	for section, val := range next {
+		old, ok := current[section]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Section: section, Added: true})
+		case !reflect.DeepEqual(old, val):
+			changes = append(changes, Change{Section: section})
+		}
+	}
+
+	for section := range current {
+		if _, ok := next[section]; !ok {
+			changes = append(changes, Change{Section: section, Removed: true})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b Change) int {
+		return strings.Compare(a.Section, b.Section)
+	})
+
+	return changes
+}
@@ -0,0 +1,69 @@
+package reload_test
+
+import (
+	"testing"
+
+	"github.com/roadrunner-server/roadrunner/v2025/internal/reload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadValid(t *testing.T) {
+	c, err := reload.Load("test/valid.yaml", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:8080", c.Viper().GetString("http.address"))
+}
+
+func TestLoadSchemaViolations(t *testing.T) {
+	_, err := reload.Load("test/invalid.yaml", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "2 schema violation(s) found")
+	assert.Contains(t, err.Error(), "logs.level")
+}
+
+func TestLoadOverride(t *testing.T) {
+	_, err := reload.Load("test/valid.yaml", []string{"logs.level=verbose"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 schema violation(s) found")
+}
+
+func TestLoadContainerOptions(t *testing.T) {
+	_, err := reload.Load("test/restart_mode.yaml", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown restart mode")
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	_, err := reload.Load("test/missing.yaml", nil)
+	require.Error(t, err)
+}
+
+func TestDiff(t *testing.T) {
+	current := map[string]any{
+		"http":   map[string]any{"address": "127.0.0.1:8080", "pool": map[string]any{"num_workers": 4}},
+		"rpc":    map[string]any{"listen": "tcp://127.0.0.1:6001"},
+		"status": map[string]any{"address": "127.0.0.1:2114"},
+	}
+
+	next := map[string]any{
+		"http": map[string]any{"address": "127.0.0.1:8080", "pool": map[string]any{"num_workers": 8}},
+		"rpc":  map[string]any{"listen": "tcp://127.0.0.1:6001"},
+		"jobs": map[string]any{"num_pollers": 2},
+	}
+
+	changes := reload.Diff(current, next)
+	assert.Equal(t, []reload.Change{
+		{Section: "http"},
+		{Section: "jobs", Added: true},
+		{Section: "status", Removed: true},
+	}, changes)
+
+	assert.Equal(t, "http", changes[0].String())
+	assert.Equal(t, "jobs (added)", changes[1].String())
+	assert.Equal(t, "status (removed)", changes[2].String())
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	cfg := map[string]any{"rpc": map[string]any{"listen": "tcp://127.0.0.1:6001"}}
+	assert.Empty(t, reload.Diff(cfg, map[string]any{"rpc": map[string]any{"listen": "tcp://127.0.0.1:6001"}}))
+}
@@ -95,3 +95,11 @@ func TestNewClient_SuccessfullyConnectedEnvDollarSyntax(t *testing.T) {
 
 	defer func() { assert.NoError(t, c.Close()) }()
 }
+
+func TestDialer_SystemdSocket(t *testing.T) {
+	conn, err := rpc.Dialer("systemd:rpc")
+
+	assert.Nil(t, conn)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "references the socket passed by systemd")
+}
@@ -0,0 +1,4 @@
+// Package rpctest serves fake plugins RPC services for the CLI commands tests,
+// the same way the RPC plugin does: net/rpc over the Goridge codec. This
+// package is for internal use only.
+package rpctest
@@ -0,0 +1,51 @@
+package rpctest
+
+import (
+	"fmt"
+	"net"
+	"net/rpc"
+	"os"
+	"path/filepath"
+	"testing"
+
+	goridgeRpc "github.com/roadrunner-server/goridge/v4/pkg/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// Serve serves the services registered by their names (e.g. "informer") until the test ends and returns the RPC
+// address in the DSN form (tcp://127.0.0.1:6001).
+func Serve(t *testing.T, services map[string]any) string {
+	t.Helper()
+
+	srv := rpc.NewServer()
+	for name, service := range services {
+		require.NoError(t, srv.RegisterName(name, service))
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, errA := ln.Accept()
+			if errA != nil {
+				return
+			}
+
+			go srv.ServeCodec(goridgeRpc.NewCodec(conn))
+		}
+	}()
+
+	return "tcp://" + ln.Addr().String()
+}
+
+// Config serves the services the same as Serve and returns the path to the configuration with the RPC address.
+func Config(t *testing.T, services map[string]any) string {
+	t.Helper()
+
+	cfg := filepath.Join(t.TempDir(), ".rr.yaml")
+	require.NoError(t, os.WriteFile(cfg, fmt.Appendf(nil, "version: \"3\"\nrpc:\n  listen: %s\n", Serve(t, services)), 0o600))
+
+	return cfg
+}
@@ -0,0 +1,4 @@
+// Package schema validates RoadRunner configuration against the JSON schema
+// bundled with the binary. It works offline: references to plugin schemas
+// which are not embedded into the main schema are not followed.
+package schema
@@ -0,0 +1,116 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	stderr "errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/roadrunner-server/roadrunner/v2025/schemas"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// ErrNoPipelineSchema is returned when the schema of the pipeline driver is not bundled with the binary, e.g. for the
+// drivers from the custom builds.
+var ErrNoPipelineSchema = stderr.New("pipeline schema of the driver is not bundled")
+
+// ValidatePipeline checks the jobs pipeline definition (a value of the jobs.pipelines section, with the driver and the
+// config keys) against the schema of its driver. The violations paths are relative to the definition.
+func (v *Validator) ValidatePipeline(def map[string]any) ([]Violation, error) {
+	driver, ok := def["driver"].(string)
+	if !ok || driver == "" {
+		return []Violation{{Path: []string{"driver"}, Message: "missing property 'driver'"}}, nil
+	}
+
+	url, ok := v.pipelines[driver]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", driver, ErrNoPipelineSchema)
+	}
+
+	sch, err := v.compiler.Compile(url)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(def)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return v.violations(sch, doc)
+}
+
+// pipelineSchemas maps the drivers to their pipeline schemas referenced from the jobs.pipelines section. Only the
+// schemas embedded into the main schema are returned.
+func pipelineSchemas(doc any, ids map[string]struct{}) map[string]string {
+	res := make(map[string]string)
+
+	patterns, _ := lookup(doc, []string{"properties", "jobs", "properties", "pipelines", "patternProperties"}).(map[string]any)
+	for _, p := range patterns {
+		pm, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		branches, _ := pm[oneOfKey].([]any)
+		for _, b := range branches {
+			bm, ok := b.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			ref, _ := bm[refKey].(string)
+			base, fragment, _ := strings.Cut(ref, "#")
+			if _, embedded := ids[base]; !embedded {
+				continue
+			}
+
+			// the compiler resolves only the root document, so the pipeline schema is addressed by the pointer from it
+			path, found := findID(doc, base, nil)
+			if !found {
+				continue
+			}
+
+			path = append(path, strings.Split(strings.Trim(fragment, "/"), "/")...)
+
+			// the driver name is the only allowed value of the driver property
+			enum, _ := lookup(doc, append(slices.Clone(path), "properties", "driver", "enum")).([]any)
+			if len(enum) != 1 {
+				continue
+			}
+
+			if driver, ok := enum[0].(string); ok {
+				res[driver] = schemas.ConfigV3URL + "#/" + strings.Join(path, "/")
+			}
+		}
+	}
+
+	return res
+}
+
+// findID returns the path to the subschema with the $id.
+func findID(doc any, id string, path []string) ([]string, bool) {
+	m, ok := doc.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+
+	if m[idKey] == id {
+		return path, true
+	}
+
+	for k, v := range m {
+		if res, found := findID(v, id, append(slices.Clone(path), k)); found {
+			return res, true
+		}
+	}
+
+	return nil, false
+}
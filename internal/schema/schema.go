@@ -0,0 +1,254 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	stderr "errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/roadrunner-server/errors"
+	"github.com/roadrunner-server/roadrunner/v2025/schemas"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+const (
+	idKey    string = "$id"
+	refKey   string = "$ref"
+	oneOfKey string = "oneOf"
+	anyOfKey string = "anyOf"
+)
+
+// Violation describes a single configuration value which does not match the schema.
+type Violation struct {
+	// Path to the invalid value, e.g. [http pool num_workers]. Empty for the document root.
+	Path []string
+	// Message describes the problem.
+	Message string
+}
+
+// Validator validates configuration documents against the embedded version 3 schema.
+type Validator struct {
+	schema   *jsonschema.Schema
+	printer  *message.Printer
+	compiler *jsonschema.Compiler
+	// driver -> URL of its pipeline schema, empty when the driver schema is not embedded
+	pipelines map[string]string
+}
+
+// NewValidator compiles the embedded configuration schema.
+func NewValidator() (*Validator, error) {
+	const op = errors.Op("schema_new_validator")
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schemas.ConfigV3()))
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	// the schema references plugins' schemas by URL, some of them are embedded (have the same $id), others are not
+	// and can't be loaded without network access
+	ids := make(map[string]struct{})
+	collectIDs(doc, ids)
+	// should be collected before the external references are stripped
+	pipelines := pipelineSchemas(doc, ids)
+	stripExternalRefs(doc, ids)
+
+	c := jsonschema.NewCompiler()
+	err = c.AddResource(schemas.ConfigV3URL, doc)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	sch, err := c.Compile(schemas.ConfigV3URL)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	return &Validator{
+		schema:    sch,
+		printer:   message.NewPrinter(language.English),
+		compiler:  c,
+		pipelines: pipelines,
+	}, nil
+}
+
+// Validate checks the configuration and returns all found violations sorted by path.
+// The configuration should be a tree of maps and slices, as produced by viper's AllSettings.
+func (v *Validator) Validate(cfg map[string]any) ([]Violation, error) {
+	// viper keeps typed slices (e.g. []string after the env expansion), the validator understands only JSON types
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return v.violations(v.schema, doc)
+}
+
+// violations validates the document and collects the leaf errors.
+func (v *Validator) violations(sch *jsonschema.Schema, doc any) ([]Violation, error) {
+	err := sch.Validate(doc)
+	if err == nil {
+		return nil, nil
+	}
+
+	var verr *jsonschema.ValidationError
+	if !stderr.As(err, &verr) {
+		return nil, err
+	}
+
+	var violations []Violation
+	seen := make(map[string]struct{})
+
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) > 0 {
+			for _, cause := range e.Causes {
+				walk(cause)
+			}
+
+			return
+		}
+
+		if weaklyTyped(e.ErrorKind, lookup(doc, e.InstanceLocation)) {
+			return
+		}
+
+		msg := e.ErrorKind.LocalizedString(v.printer)
+		key := strings.Join(e.InstanceLocation, "\x00") + "\x00" + msg
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+
+		violations = append(violations, Violation{
+			Path:    e.InstanceLocation,
+			Message: msg,
+		})
+	}
+
+	walk(verr)
+
+	slices.SortStableFunc(violations, func(a, b Violation) int {
+		return slices.Compare(a.Path, b.Path)
+	})
+
+	return violations, nil
+}
+
+// weaklyTyped reports whether the type mismatch is caused by a string value which RR would decode into the wanted
+// type anyway. Values coming from the environment variables or -o flags are always strings.
+func weaklyTyped(k jsonschema.ErrorKind, val any) bool {
+	t, ok := k.(*kind.Type)
+	if !ok || t.Got != "string" {
+		return false
+	}
+
+	str, ok := val.(string)
+	if !ok {
+		return false
+	}
+
+	for _, want := range t.Want {
+		switch want {
+		case "integer":
+			if _, err := strconv.ParseInt(str, 10, 64); err == nil {
+				return true
+			}
+		case "number":
+			if _, err := strconv.ParseFloat(str, 64); err == nil {
+				return true
+			}
+		case "boolean":
+			if _, err := strconv.ParseBool(str); err == nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// lookup returns the value located by the JSON pointer tokens.
+func lookup(doc any, path []string) any {
+	for _, p := range path {
+		switch t := doc.(type) {
+		case map[string]any:
+			doc = t[p]
+		case []any:
+			idx, err := strconv.Atoi(p)
+			if err != nil || idx < 0 || idx >= len(t) {
+				return nil
+			}
+			doc = t[idx]
+		default:
+			return nil
+		}
+	}
+
+	return doc
+}
+
+func collectIDs(doc any, ids map[string]struct{}) {
+	switch t := doc.(type) {
+	case map[string]any:
+		if id, ok := t[idKey].(string); ok {
+			ids[id] = struct{}{}
+		}
+		for _, v := range t {
+			collectIDs(v, ids)
+		}
+	case []any:
+		for _, v := range t {
+			collectIDs(v, ids)
+		}
+	}
+}
+
+// stripExternalRefs removes references to the documents which are not embedded into the schema. Such values are
+// accepted as is. A oneOf/anyOf with a stripped branch would match anything (or match several branches at once), so
+// it's removed as well.
+func stripExternalRefs(doc any, ids map[string]struct{}) bool {
+	switch t := doc.(type) {
+	case map[string]any:
+		stripped := false
+		if ref, ok := t[refKey].(string); ok && !strings.HasPrefix(ref, "#") {
+			base, _, _ := strings.Cut(ref, "#")
+			if _, embedded := ids[base]; !embedded {
+				delete(t, refKey)
+				stripped = true
+			}
+		}
+
+		for k, v := range t {
+			branches, ok := v.([]any)
+			if ok && (k == oneOfKey || k == anyOfKey) {
+				for _, b := range branches {
+					if stripExternalRefs(b, ids) {
+						delete(t, k)
+					}
+				}
+
+				continue
+			}
+
+			stripExternalRefs(v, ids)
+		}
+
+		return stripped
+	case []any:
+		for _, v := range t {
+			stripExternalRefs(v, ids)
+		}
+	}
+
+	return false
+}
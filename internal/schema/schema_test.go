@@ -0,0 +1,121 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/roadrunner-server/roadrunner/v2025/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	v, err := schema.NewValidator()
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name     string
+		cfg      map[string]any
+		wantPath [][]string
+	}{
+		{
+			name: "valid",
+			cfg: map[string]any{
+				"version": "3",
+				"rpc":     map[string]any{"listen": "tcp://127.0.0.1:6001"},
+				"http":    map[string]any{"address": "127.0.0.1:8080", "pool": map[string]any{"num_workers": 4}},
+			},
+		},
+		{
+			name: "string values from env are weakly typed",
+			cfg: map[string]any{
+				"version": "3",
+				"http":    map[string]any{"address": "127.0.0.1:8080", "max_request_size": "100"},
+			},
+		},
+		{
+			name:     "missing version",
+			cfg:      map[string]any{"rpc": map[string]any{"listen": "tcp://127.0.0.1:6001"}},
+			wantPath: [][]string{{}},
+		},
+		{
+			name: "wrong type",
+			cfg: map[string]any{
+				"version": "3",
+				"logs":    map[string]any{"level": "verbose"},
+			},
+			wantPath: [][]string{{"logs", "level"}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := v.Validate(tt.cfg)
+			require.NoError(t, err)
+			require.Len(t, violations, len(tt.wantPath))
+
+			for i := range violations {
+				assert.Equal(t, tt.wantPath[i], violations[i].Path)
+				assert.NotEmpty(t, violations[i].Message)
+			}
+		})
+	}
+}
+
+func TestValidatePipeline(t *testing.T) {
+	v, err := schema.NewValidator()
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name     string
+		def      map[string]any
+		wantPath [][]string
+		wantErr  error
+	}{
+		{
+			name: "valid",
+			def: map[string]any{
+				"driver": "amqp",
+				"config": map[string]any{"queue": "emails", "priority": 5, "durable": true},
+			},
+		},
+		{
+			name: "weakly typed values from the flags",
+			def: map[string]any{
+				"driver": "amqp",
+				"config": map[string]any{"priority": "5", "durable": "true"},
+			},
+		},
+		{
+			name:     "missing driver",
+			def:      map[string]any{"config": map[string]any{"queue": "emails"}},
+			wantPath: [][]string{{"driver"}},
+		},
+		{
+			name: "unknown option and wrong value",
+			def: map[string]any{
+				"driver": "amqp",
+				"config": map[string]any{"queue": "emails", "priority": -1, "no_such_option": 1},
+			},
+			wantPath: [][]string{{"config"}, {"config", "priority"}},
+		},
+		{
+			name:    "driver without the bundled schema",
+			def:     map[string]any{"driver": "memory"},
+			wantErr: schema.ErrNoPipelineSchema,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := v.ValidatePipeline(tt.def)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, violations, len(tt.wantPath), "%v", violations)
+
+			for i := range violations {
+				assert.Equal(t, tt.wantPath[i], violations[i].Path)
+				assert.NotEmpty(t, violations[i].Message)
+			}
+		})
+	}
+}
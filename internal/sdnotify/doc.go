@@ -1,6 +1,8 @@
 // Package sdnotify provides a Go implementation of the sd_notify protocol.
 // It can be used to inform systemd of service start-up completion, watchdog
-// events, and other status changes.
+// events, and other status changes: STATUS=, MAINPID=, EXTEND_TIMEOUT_USEC=
+// and RELOADING=1 with MONOTONIC_USEC= for Type=notify-reload. Both the
+// filesystem and the abstract namespace (@) sockets are supported.
 //
 // https://www.freedesktop.org/software/systemd/man/sd_notify.html#Description
 package sdnotify
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package sdnotify
+
+// monotonicUsec: the service manager runs on Linux only.
+func monotonicUsec() (int64, bool) {
+	return 0, false
+}
@@ -18,9 +18,15 @@
 package sdnotify
 
 import (
+	"fmt"
 	"net"
 	"os"
+	"runtime"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/roadrunner-server/errors"
 )
 
 type State string
@@ -45,22 +51,57 @@ const (
 	Watchdog = "WATCHDOG=1"
 )
 
+// Status describes the service state in a free form, it's shown by `systemctl status`.
+func Status(msg string) State {
+	return State("STATUS=" + msg)
+}
+
+// MainPID tells the service manager the main PID of the service, e.g. after the process is replaced.
+func MainPID(pid int) State {
+	return State("MAINPID=" + strconv.Itoa(pid))
+}
+
+// ExtendTimeout tells the service manager to extend the startup, runtime or shutdown timeout corresponding to the
+// current state: the next message is expected within d.
+func ExtendTimeout(d time.Duration) State {
+	return State("EXTEND_TIMEOUT_USEC=" + strconv.FormatInt(d.Microseconds(), 10))
+}
+
+// ReloadingNow is Reloading with the CLOCK_MONOTONIC timestamp of the reload start, required by Type=notify-reload.
+// The timestamp is omitted when the platform has no monotonic clock to report.
+func ReloadingNow() State {
+	usec, ok := monotonicUsec()
+	if !ok {
+		return Reloading
+	}
+
+	return Join(Reloading, State(fmt.Sprintf("MONOTONIC_USEC=%d", usec)))
+}
+
+// Join combines the states into a single message, e.g. READY=1 with STATUS=.
+func Join(states ...State) State {
+	s := make([]string, 0, len(states))
+	for _, st := range states {
+		s = append(s, string(st))
+	}
+
+	return State(strings.Join(s, "\n"))
+}
+
 // SdNotify sends a message to the init daemon. It is common to ignore the error.
-// If `unsetEnvironment` is true, the environment variable `NOTIFY_SOCKET`
-// will be unconditionally unset.
 //
 // It returns one of the following:
 // (false, nil) - notification not supported (i.e. NOTIFY_SOCKET is unset)
 // (false, err) - notification supported, but failure happened (e.g. error connecting to NOTIFY_SOCKET or while sending data)
 // (true, nil) - notification supported, data has been sent
 func SdNotify(state State) (bool, error) {
-	socketAddr := &net.UnixAddr{
-		Name: os.Getenv("NOTIFY_SOCKET"),
-		Net:  "unixgram",
+	socketAddr, err := notifySocket()
+	if err != nil {
+		return false, err
 	}
 
 	// NOTIFY_SOCKET not set
-	if socketAddr.Name == "" {
+	if socketAddr == nil {
 		return false, nil
 	}
 
@@ -81,9 +122,59 @@ func SdNotify(state State) (bool, error) {
 	return true, nil
 }
 
-func StartWatchdog(interval int, stopCh <-chan struct{}) {
+// notifySocket returns the address of NOTIFY_SOCKET, nil when it's not set. The path starting with @ is the abstract
+// namespace socket (Linux only): the leading @ is replaced with the NUL byte by the net package.
+func notifySocket() (*net.UnixAddr, error) {
+	name := os.Getenv("NOTIFY_SOCKET")
+
+	switch {
+	case name == "":
+		return nil, nil
+	case strings.HasPrefix(name, "@"):
+		if runtime.GOOS != "linux" {
+			return nil, errors.Errorf("abstract NOTIFY_SOCKET %s is supported on Linux only", name)
+		}
+	case !strings.HasPrefix(name, "/"):
+		// e.g. vsock:, used by the virtual machines
+		return nil, errors.Errorf("unsupported NOTIFY_SOCKET address: %s", name)
+	}
+
+	return &net.UnixAddr{Name: name, Net: "unixgram"}, nil
+}
+
+// WatchdogTimeout returns the watchdog timeout set by the service manager (WatchdogSec=), zero when the watchdog is
+// disabled or it's enabled for another process (WATCHDOG_PID).
+func WatchdogTimeout() (time.Duration, error) {
+	usec := os.Getenv("WATCHDOG_USEC")
+	if usec == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.ParseUint(usec, 10, 63)
+	if err != nil || n == 0 {
+		return 0, errors.Errorf("invalid WATCHDOG_USEC: %q", usec)
+	}
+
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" {
+		p, errP := strconv.Atoi(pid)
+		if errP != nil {
+			return 0, errors.Errorf("invalid WATCHDOG_PID: %q", pid)
+		}
+
+		if p != os.Getpid() {
+			return 0, nil
+		}
+	}
+
+	return time.Duration(n) * time.Microsecond, nil //nolint:gosec
+}
+
+// StartWatchdog pings the service manager every interval until stopCh is closed or the notification fails. When
+// healthy is set, the ping is skipped while it returns false, so the service manager restarts the service once the
+// watchdog timeout expires.
+func StartWatchdog(interval time.Duration, stopCh <-chan struct{}, healthy func() bool) {
 	go func() {
-		ticker := time.NewTicker(time.Duration(interval) * time.Second)
+		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
 
 		for {
@@ -91,6 +182,10 @@ func StartWatchdog(interval int, stopCh <-chan struct{}) {
 			case <-stopCh:
 				return
 			case <-ticker.C:
+				if healthy != nil && !healthy() {
+					continue
+				}
+
 				supported, err := SdNotify(Watchdog)
 				if err != nil {
 					return
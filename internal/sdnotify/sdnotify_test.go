@@ -0,0 +1,202 @@
+//go:build !windows
+
+package sdnotify_test
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/roadrunner-server/roadrunner/v2025/internal/sdnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listen plays the service manager: it listens on the unixgram socket set as NOTIFY_SOCKET.
+func listen(t *testing.T, name string) *net.UnixConn {
+	t.Helper()
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: name, Net: "unixgram"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	t.Setenv("NOTIFY_SOCKET", name)
+
+	return conn
+}
+
+func receive(t *testing.T, conn *net.UnixConn) string {
+	t.Helper()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second*5)))
+
+	buf := make([]byte, 4096)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+
+	return string(buf[:n])
+}
+
+func socketPath(t *testing.T) string {
+	t.Helper()
+
+	// the socket path length is limited, t.TempDir might be too long on macOS
+	dir, err := os.MkdirTemp("", "sd")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	return filepath.Join(dir, "notify.sock")
+}
+
+func TestSdNotify(t *testing.T) {
+	conn := listen(t, socketPath(t))
+
+	tests := []struct {
+		state    sdnotify.State
+		expected string
+	}{
+		{state: sdnotify.Ready, expected: "READY=1"},
+		{state: sdnotify.Status("serving, 8 http workers, 3 pipelines"), expected: "STATUS=serving, 8 http workers, 3 pipelines"},
+		{state: sdnotify.MainPID(42), expected: "MAINPID=42"},
+		{state: sdnotify.ExtendTimeout(time.Second * 90), expected: "EXTEND_TIMEOUT_USEC=90000000"},
+		{state: sdnotify.Join(sdnotify.Ready, sdnotify.Status("serving")), expected: "READY=1\nSTATUS=serving"},
+	}
+
+	for _, tt := range tests {
+		notified, err := sdnotify.SdNotify(tt.state)
+		require.NoError(t, err)
+		assert.True(t, notified)
+
+		assert.Equal(t, tt.expected, receive(t, conn))
+	}
+}
+
+func TestSdNotifyUnset(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+
+	notified, err := sdnotify.SdNotify(sdnotify.Ready)
+	require.NoError(t, err)
+	assert.False(t, notified)
+}
+
+func TestSdNotifyUnsupportedAddress(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "vsock:2:1234")
+
+	notified, err := sdnotify.SdNotify(sdnotify.Ready)
+	require.Error(t, err)
+	assert.False(t, notified)
+	assert.Contains(t, err.Error(), "unsupported NOTIFY_SOCKET address")
+}
+
+func TestSdNotifyAbstractSocket(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("abstract sockets are supported on Linux only")
+	}
+
+	conn := listen(t, fmt.Sprintf("@rr-sdnotify-test-%d", os.Getpid()))
+
+	notified, err := sdnotify.SdNotify(sdnotify.Stopping)
+	require.NoError(t, err)
+	assert.True(t, notified)
+
+	assert.Equal(t, "STOPPING=1", receive(t, conn))
+}
+
+func TestReloadingNow(t *testing.T) {
+	conn := listen(t, socketPath(t))
+
+	_, err := sdnotify.SdNotify(sdnotify.ReloadingNow())
+	require.NoError(t, err)
+
+	msg := receive(t, conn)
+	if runtime.GOOS != "linux" {
+		assert.Equal(t, "RELOADING=1", msg)
+		return
+	}
+
+	lines := strings.Split(msg, "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "RELOADING=1", lines[0])
+
+	usec, ok := strings.CutPrefix(lines[1], "MONOTONIC_USEC=")
+	require.True(t, ok, lines[1])
+
+	n, err := strconv.ParseInt(usec, 10, 64)
+	require.NoError(t, err)
+	assert.Positive(t, n)
+}
+
+func TestWatchdogTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		usec     string
+		pid      string
+		expected time.Duration
+		err      string
+	}{
+		{name: "disabled"},
+		{name: "enabled", usec: "30000000", expected: time.Second * 30},
+		{name: "this process", usec: "2000000", pid: strconv.Itoa(os.Getpid()), expected: time.Second * 2},
+		{name: "another process", usec: "2000000", pid: strconv.Itoa(os.Getpid() + 1)},
+		{name: "invalid usec", usec: "abc", err: "invalid WATCHDOG_USEC"},
+		{name: "zero usec", usec: "0", err: "invalid WATCHDOG_USEC"},
+		{name: "invalid pid", usec: "2000000", pid: "abc", err: "invalid WATCHDOG_PID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("WATCHDOG_USEC", tt.usec)
+			t.Setenv("WATCHDOG_PID", tt.pid)
+
+			timeout, err := sdnotify.WatchdogTimeout()
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, timeout)
+		})
+	}
+}
+
+func TestStartWatchdog(t *testing.T) {
+	conn := listen(t, socketPath(t))
+
+	stopCh := make(chan struct{})
+	sdnotify.StartWatchdog(time.Millisecond*50, stopCh, nil)
+
+	assert.Equal(t, "WATCHDOG=1", receive(t, conn))
+	assert.Equal(t, "WATCHDOG=1", receive(t, conn))
+
+	close(stopCh)
+}
+
+func TestStartWatchdogUnhealthy(t *testing.T) {
+	conn := listen(t, socketPath(t))
+
+	var healthy atomic.Bool
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	sdnotify.StartWatchdog(time.Millisecond*50, stopCh, healthy.Load)
+
+	// no pings while unhealthy
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Millisecond*300)))
+	_, err := conn.Read(make([]byte, 64))
+	require.Error(t, err)
+
+	// resumed after the recovery
+	healthy.Store(true)
+	assert.Equal(t, "WATCHDOG=1", receive(t, conn))
+}
@@ -4,9 +4,10 @@ import (
 	"fmt"
 	"runtime/debug"
 
-	configImpl "github.com/roadrunner-server/config/v6"
 	"github.com/roadrunner-server/endure/v2"
 	"github.com/roadrunner-server/roadrunner/v2025/container"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/configurer"
+	"github.com/roadrunner-server/roadrunner/v2025/internal/loader"
 )
 
 const (
@@ -21,16 +22,21 @@ type RR struct {
 
 // NewRR creates a new RR instance that can then be started or stopped by the caller
 func NewRR(cfgFile string, override []string, pluginList []any) (*RR, error) {
+	// resolve the configuration once: the container and all the plugins see the same values
+	resolved, err := loader.Load(cfgFile, override)
+	if err != nil {
+		return nil, err
+	}
+
 	// create endure container config
-	containerCfg, err := container.NewConfig(cfgFile)
+	containerCfg, err := container.ParseConfig(resolved.Viper())
 	if err != nil {
 		return nil, err
 	}
 
-	cfg := &configImpl.Plugin{
-		Path:    cfgFile,
+	cfg := &configurer.Plugin{
+		Config:  resolved,
 		Timeout: containerCfg.GracePeriod,
-		Flags:   override,
 		Version: getRRVersion(),
 	}
 
@@ -0,0 +1,3 @@
+// Package schemas embeds the public JSON schemas of the RoadRunner
+// configuration file, so they can be used without network access.
+package schemas
@@ -0,0 +1,16 @@
+package schemas
+
+import (
+	_ "embed"
+)
+
+// ConfigV3URL is the $id of the version 3 configuration schema.
+const ConfigV3URL string = "https://raw.githubusercontent.com/roadrunner-server/roadrunner/refs/heads/master/schemas/config/3.0.schema.json"
+
+//go:embed config/3.0.schema.json
+var configV3 []byte
+
+// ConfigV3 returns the JSON schema of the version 3 configuration file.
+func ConfigV3() []byte {
+	return configV3
+}